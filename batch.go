@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink 保存批量生成的头像
+type Sink interface {
+	// Put 保存 key 对应的头像
+	//
+	// content 为以 format 格式编码之后的内容，在 Put 返回之后会被复用，不能在 Put 之外引用。
+	// Batch 只会在同一个 goroutine 中依次调用 Put，实现者无须考虑并发。
+	Put(key string, format Format, content []byte) error
+}
+
+// SinkFunc 将函数转换为 Sink
+type SinkFunc func(key string, format Format, content []byte) error
+
+// BatchOptions Batch 的参数
+type BatchOptions struct {
+	// 并发的数量，小于等于 0 表示采用 runtime.NumCPU()。
+	Workers int
+
+	// 头像的格式
+	Format Format
+
+	// 保存头像的位置，不能为空。
+	Sink Sink
+
+	// 每处理完一个 key 都会调用，可以为空。
+	Progress func(BatchResult)
+
+	// 生成或是保存某个 key 对应的头像失败时调用，可以为空。
+	//
+	// 单个 key 的错误不会中断整个处理过程。
+	OnError func(key string, err error)
+}
+
+// BatchResult 批量处理的结果
+type BatchResult struct {
+	Done   int // 成功的数量
+	Failed int // 失败的数量
+}
+
+type batchJob struct {
+	key string
+	buf *bytes.Buffer
+	err error
+}
+
+func (f SinkFunc) Put(key string, format Format, content []byte) error {
+	return f(key, format, content)
+}
+
+// FileName 返回 key 以 format 格式保存时的文件名
+//
+// key 中除了 ASCII 字母、数字、- 和 _ 之外的字节都会以 %XX 的形式转义，
+// 与 Windows 保留的设备名(比如 CON 和 NUL)相同时，其第一个字符也会被转义，
+// 保证在各个平台上都是合法的文件名。扩展名由 format 决定，比如 caixw%2F1.png。
+func FileName(key string, format Format) string {
+	const hex = "0123456789ABCDEF"
+
+	reserved := reservedFileNames[strings.ToUpper(key)]
+	name := make([]byte, 0, len(key)+1+len(format.String()))
+	for index := 0; index < len(key); index++ {
+		c := key[index]
+		if fileNameChar(c) && (index > 0 || !reserved) {
+			name = append(name, c)
+		} else {
+			name = append(name, '%', hex[c>>4], hex[c&0xf])
+		}
+	}
+	return string(name) + "." + format.String()
+}
+
+// Windows 中不能作为文件名的设备名
+var reservedFileNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func fileNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// DirSink 将头像保存到目录 dir 中
+//
+// 文件名由 FileName 决定，已经存在的文件会被覆盖。
+func DirSink(dir string) Sink {
+	return SinkFunc(func(key string, format Format, content []byte) error {
+		return os.WriteFile(filepath.Join(dir, FileName(key, format)), content, 0o644)
+	})
+}
+
+// TarSink 将头像写入 tar 文件
+//
+// 文件名由 FileName 决定，w 需要由调用方关闭。
+func TarSink(w *tar.Writer) Sink {
+	now := time.Now()
+	return SinkFunc(func(key string, format Format, content []byte) error {
+		err := w.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     FileName(key, format),
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			ModTime:  now,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	})
+}
+
+// ZipSink 将头像写入 zip 文件
+//
+// 文件名由 FileName 决定，w 需要由调用方关闭。
+// 图片格式本身已经过压缩，所以采用 zip.Store 的方式保存。
+func ZipSink(w *zip.Writer) Sink {
+	now := time.Now()
+	return SinkFunc(func(key string, format Format, content []byte) error {
+		f, err := w.CreateHeader(&zip.FileHeader{
+			Name:     FileName(key, format),
+			Method:   zip.Store,
+			Modified: now,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		return err
+	})
+}
+
+// Keys 将迭代函数转换为 Batch 所需的通道
+//
+// 依次调用 next，直到其第二个返回值为 false 或是 ctx 被取消，之后关闭通道。
+// 因 ctx 被取消而关闭的通道，在 Batch 看来与 key 已经全部生成并无区别，
+// 与 Batch 采用同一个 ctx 时，如果取消时没有丢弃任何头像，Batch 不会返回错误，是否被取消应当以 ctx.Err() 为准。
+func Keys(ctx context.Context, next func() (string, bool)) <-chan string {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		for {
+			key, ok := next()
+			if !ok {
+				return
+			}
+
+			select {
+			case keys <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// Batch 批量生成 keys 中所有 key 对应的头像
+//
+// 以 o.Workers 个 goroutine 并发生成头像，并依次交由 o.Sink 保存，直到 keys 被关闭或是 ctx 被取消。
+// ctx 被取消时，尚未保存的头像会被丢弃，并返回 ctx.Err()，
+// 但如果取消时 keys 已经关闭，且其中所有的 key 都已经保存，则不会返回错误；
+// 单个 key 的错误只会通过 o.OnError 报告，并计入返回值的 Failed 中。
+func (i *Identicon) Batch(ctx context.Context, keys <-chan string, o *BatchOptions) (BatchResult, error) {
+	if o.Sink == nil {
+		panic("参数 o.Sink 不能为空")
+	}
+	encoder := o.Format.Encoder()
+
+	workers := o.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var dropped int32 // 是否有 key 因为 ctx 被取消而未保存
+	jobs := make(chan *batchJob, workers)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var key string
+				var ok bool
+				select {
+				case <-ctx.Done():
+					if !drained(keys) {
+						atomic.StoreInt32(&dropped, 1)
+					}
+					return
+				case key, ok = <-keys:
+					if !ok {
+						return
+					}
+				}
+
+				buf := bufferPool.Get().(*bytes.Buffer)
+				buf.Reset()
+				err := encoder.Encode(buf, i, []byte(key))
+
+				select {
+				case jobs <- &batchJob{key: key, buf: buf, err: err}:
+				case <-ctx.Done():
+					putBuffer(buf)
+					atomic.StoreInt32(&dropped, 1)
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(jobs)
+	}()
+
+	var result BatchResult
+	for job := range jobs {
+		if ctx.Err() != nil { // 取消之后依然需要读完 jobs，以免 goroutine 无法退出。
+			putBuffer(job.buf)
+			atomic.StoreInt32(&dropped, 1)
+			continue
+		}
+
+		err := job.err
+		if err == nil {
+			err = o.Sink.Put(job.key, o.Format, job.buf.Bytes())
+		}
+		putBuffer(job.buf)
+
+		if err != nil {
+			result.Failed++
+			if o.OnError != nil {
+				o.OnError(job.key, err)
+			}
+		} else {
+			result.Done++
+		}
+		if o.Progress != nil {
+			o.Progress(result)
+		}
+	}
+
+	if atomic.LoadInt32(&dropped) == 1 {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// keys 是否已经关闭且没有剩余的内容
+//
+// 仅用于 ctx 被取消之后，读取的 key 会被丢弃。
+// keys 未关闭时，即使暂时没有内容，也有可能在之后写入，同样返回 false。
+func drained(keys <-chan string) bool {
+	select {
+	case _, ok := <-keys:
+		return !ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 返回包含 n 个 key 的通道
+func batchKeys(n int) <-chan string {
+	var index int
+	return Keys(context.Background(), func() (string, bool) {
+		if index >= n {
+			return "", false
+		}
+		index++
+		return "user/" + strconv.Itoa(index-1), true
+	})
+}
+
+func TestFileName(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(FileName("caixw", FormatPNG), "caixw.png").
+		Equal(FileName("user/1", FormatSVG), "user%2F1.svg").
+		Equal(FileName("a b", FormatJPEG), "a%20b.jpeg").
+		Equal(FileName("a_b-1", FormatPNG), "a_b-1.png").
+		Equal(FileName("..", FormatPNG), "%2E%2E.png").
+		Equal(FileName(`a:b*c?d"e<f>g|h\i`, FormatPNG), "a%3Ab%2Ac%3Fd%22e%3Cf%3Eg%7Ch%5Ci.png").
+		Equal(FileName("caixw@example.com", FormatPNG), "caixw%40example%2Ecom.png").
+		Equal(FileName("100%", FormatPNG), "100%25.png").
+		Equal(FileName("张", FormatPNG), "%E5%BC%A0.png").
+		Equal(FileName("con", FormatPNG), "%63on.png").
+		Equal(FileName("Lpt1", FormatPNG), "%4Cpt1.png").
+		Equal(FileName("console", FormatPNG), "console.png")
+}
+
+func TestIdenticon_Batch(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2V2, size, back, fore)
+
+	contents := map[string][]byte{}
+	var progress int
+	r, err := ii.Batch(context.Background(), batchKeys(100), &BatchOptions{
+		Workers: 4,
+		Sink: SinkFunc(func(key string, format Format, content []byte) error {
+			a.Equal(format, FormatPNG)
+			contents[key] = append([]byte{}, content...)
+			return nil
+		}),
+		Progress: func(r BatchResult) {
+			progress++
+			a.Equal(r.Done+r.Failed, progress)
+		},
+	})
+	a.NotError(err).
+		Equal(r, BatchResult{Done: 100}).
+		Equal(progress, 100).
+		Length(contents, 100)
+
+	// 内容与 Write 的相同
+	buf := &bytes.Buffer{}
+	a.NotError(ii.Write(buf, []byte("user/5"), FormatPNG))
+	a.Equal(contents["user/5"], buf.Bytes())
+
+	// 单个 key 的错误
+	var failed []string
+	r, err = ii.Batch(context.Background(), batchKeys(10), &BatchOptions{
+		Sink: SinkFunc(func(key string, _ Format, _ []byte) error {
+			if key == "user/3" {
+				return errors.New("sink error")
+			}
+			return nil
+		}),
+		OnError: func(key string, err error) {
+			failed = append(failed, key+":"+err.Error())
+		},
+	})
+	a.NotError(err).
+		Equal(r, BatchResult{Done: 9, Failed: 1}).
+		Equal(failed, []string{"user/3:sink error"})
+
+	a.Panic(func() {
+		ii.Batch(context.Background(), batchKeys(1), &BatchOptions{})
+	})
+	a.Panic(func() {
+		ii.Batch(context.Background(), batchKeys(1), &BatchOptions{Sink: DirSink(""), Format: 100})
+	})
+}
+
+func TestIdenticon_Batch_cancel(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2V2, size, back, fore)
+
+	// 无限的 key，在保存了 10 个之后取消。
+	// keys 采用单独的 ctx，保证取消时 keys 依然未关闭，即必然有 key 被丢弃。
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keysCtx, keysCancel := context.WithCancel(context.Background())
+	defer keysCancel()
+	var generated int64
+	keys := Keys(keysCtx, func() (string, bool) {
+		return strconv.FormatInt(atomic.AddInt64(&generated, 1), 10), true
+	})
+
+	r, err := ii.Batch(ctx, keys, &BatchOptions{
+		Workers: 2,
+		Sink: SinkFunc(func(string, Format, []byte) error {
+			return nil
+		}),
+		Progress: func(r BatchResult) {
+			if r.Done == 10 {
+				cancel()
+			}
+		},
+	})
+	a.ErrorIs(err, context.Canceled).Equal(r, BatchResult{Done: 10})
+
+	// keysCtx 取消之后 keys 会被关闭
+	keysCancel()
+	for range keys {
+	}
+
+	// keys 已经关闭，且所有的 key 都已经保存之后才取消，不会返回错误。
+	for index := 0; index < 20; index++ {
+		closed := make(chan string, 5)
+		for k := 0; k < 5; k++ {
+			closed <- strconv.Itoa(k)
+		}
+		close(closed)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		r, err := ii.Batch(ctx, closed, &BatchOptions{
+			Workers: 3,
+			Sink: SinkFunc(func(string, Format, []byte) error {
+				return nil
+			}),
+			Progress: func(r BatchResult) {
+				if r.Done == 5 {
+					cancel()
+				}
+			},
+		})
+		a.NotError(err).Equal(r, BatchResult{Done: 5})
+		cancel()
+	}
+}
+
+func TestSinks(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2V2, size, back, fore)
+	opt := &BatchOptions{Format: FormatSVG}
+
+	// DirSink
+	dir := t.TempDir()
+	opt.Sink = DirSink(dir)
+	r, err := ii.Batch(context.Background(), batchKeys(5), opt)
+	a.NotError(err).Equal(r.Done, 5)
+	content, err := os.ReadFile(filepath.Join(dir, "user%2F2.svg"))
+	a.NotError(err)
+	buf := &bytes.Buffer{}
+	a.NotError(ii.Write(buf, []byte("user/2"), FormatSVG))
+	a.Equal(content, buf.Bytes())
+
+	// TarSink
+	tarBuf := &bytes.Buffer{}
+	tw := tar.NewWriter(tarBuf)
+	opt.Sink = TarSink(tw)
+	r, err = ii.Batch(context.Background(), batchKeys(5), opt)
+	a.NotError(err).Equal(r.Done, 5)
+	a.NotError(tw.Close())
+
+	tr := tar.NewReader(tarBuf)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		a.NotError(err)
+		files[hdr.Name], err = io.ReadAll(tr)
+		a.NotError(err)
+	}
+	a.Length(files, 5).Equal(files["user%2F2.svg"], content)
+
+	// ZipSink
+	zipBuf := &bytes.Buffer{}
+	zw := zip.NewWriter(zipBuf)
+	opt.Sink = ZipSink(zw)
+	r, err = ii.Batch(context.Background(), batchKeys(5), opt)
+	a.NotError(err).Equal(r.Done, 5)
+	a.NotError(zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(zipBuf.Bytes()), int64(zipBuf.Len()))
+	a.NotError(err).Length(zr.File, 5)
+	f, err := zr.Open("user%2F2.svg")
+	a.NotError(err)
+	zc, err := io.ReadAll(f)
+	a.NotError(err).Equal(zc, content)
+}
@@ -32,6 +32,18 @@ func BenchmarkIdenticon_Make_v1(b *testing.B) {
 	}
 }
 
+func BenchmarkIdenticon_Make_v1_precompute(b *testing.B) {
+	a := assert.New(b, false)
+
+	ii := S1(size).Precompute()
+	a.NotNil(ii)
+
+	for i := 0; i < b.N; i++ {
+		img := ii.Make([]byte("Make"))
+		a.NotNil(img)
+	}
+}
+
 func BenchmarkIdenticon_Rand_v2(b *testing.B) {
 	a := assert.New(b, false)
 	r := rand.New(rand.NewSource(time.Now().Unix()))
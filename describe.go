@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"strconv"
+
+	"github.com/issue9/identicon/v2/internal/style1"
+	"github.com/issue9/identicon/v2/internal/style3"
+	"github.com/issue9/identicon/v2/internal/style6"
+	"github.com/issue9/identicon/v2/internal/style7"
+)
+
+// Description 头像的生成细节
+//
+// 记录了 Make 在生成头像时所作的各项选择，可以直接序列化为 JSON，
+// 方便在管理后台或是问题报告中展示。
+type Description struct {
+	Style Style  `json:"style"`
+	Sum   uint64 `json:"sum"`   // data 的 hash 值
+	Color int    `json:"color"` // 前景色在 New 的参数 fore 中的下标
+
+	// 以下仅在 Style1 中有值
+	//
+	// Symmetry 为对称方式，可以是 rotate、mirror-x、mirror-xy、diagonal 和 none；
+	// Corner 和 Edge 分别为左上角和顶部中间的方块，对称方式为 rotate 时，
+	// 其它角落和边上的方块由这两者依次顺时针旋转 90 度得到；
+	// 其它对称方式则由 Blocks 给出四个角和四条边上各个位置的方块，从左上角开始顺时针排列，
+	// 其中镜像的区域在绘制之后会被左侧、上方或是对角线左下方的内容覆盖。
+	Symmetry string   `json:"symmetry,omitempty"`
+	Corner   *Block   `json:"corner,omitempty"`
+	Edge     *Block   `json:"edge,omitempty"`
+	Center   *Block   `json:"center,omitempty"`
+	Blocks   []*Block `json:"blocks,omitempty"`
+
+	// 以下仅在 Style2 和 Style6 中有值
+	//
+	// 点阵每一行的位掩码，行数与列数相同，每个元素的低位部分从高到低依次表示从左到右的各个点，
+	// 1 表示前景色，0 表示背景色。可以通过 FormatRows 转换为字符串的形式。
+	Rows []uint16 `json:"rows,omitempty"`
+
+	// 以下仅在 Style3、Style4、Style5 和 Style7 中有值
+	//
+	// 以文本表示的图案，每个元素表示一行。
+	// Style3 中每一行为从左到右排列的三角形，1 表示前景色，0 表示背景色，各行的长度并不相同；
+	// Style4 中每个字符为对应图块顺时针旋转 90 度的次数；
+	// Style5 为迷宫的网格，坐标均为奇数的是格子，其它的为墙壁或是打通的通路；
+	// Style7 为从内到外各个圆环的图案。
+	Lines []string `json:"lines,omitempty"`
+
+	// 以下仅在 Style3 和 Style7 中有值
+	//
+	// 旋转对称的重数，Style3 为 6 或是 3，Style7 为 5、6、8 或是 12。
+	Fold int `json:"fold,omitempty"`
+
+	// 以下仅在 Style4 中有值
+	Tile string `json:"tile,omitempty"` // 图块的图案，可以是 arc、diagonal 和 triangle。
+
+	// 以下仅在 Style6 中有值
+	Rule int `json:"rule,omitempty"` // 元胞自动机的规则编号
+}
+
+// Block Style1 中方块的信息
+type Block struct {
+	Index int `json:"index"` // 方块的编号
+	Angle int `json:"angle"` // 旋转的角度，可以是 0、90、180 和 270。
+}
+
+// Describe 返回根据 data 生成头像的细节
+//
+// 返回值描述的内容与 Make(data) 生成的图片是一致的。
+func (i *Identicon) Describe(data []byte) *Description {
+	sum := i.sum(data)
+	d := &Description{
+		Style: i.style,
+		Sum:   sum,
+		Color: i.foreIndex(sum),
+	}
+
+	switch i.style.base() {
+	case Style1:
+		c := style1.Choose(i.size, uint32(sum), i.style.style1Version(), i.symmetry)
+		d.Corner = &Block{Index: c.Corner, Angle: c.CornerAngle * 90}
+		d.Edge = &Block{Index: c.Edge, Angle: c.EdgeAngle * 90}
+		d.Center = &Block{Index: c.Center}
+		d.Symmetry = i.symmetry.String()
+		if i.symmetry != SymmetryRotate {
+			d.Blocks = make([]*Block, 0, len(c.Cells))
+			for _, cell := range c.Cells {
+				d.Blocks = append(d.Blocks, &Block{Index: cell.Block, Angle: cell.Angle * 90})
+			}
+		}
+	case Style2, Style6:
+		if i.style.base() == Style6 {
+			d.Rule = int(style6.New(uint32(sum), i.blocks, !i.asymmetric).Rule())
+		}
+
+		d.Rows = append([]uint16{}, i.matrix(data, sum).Lines()...)
+	case Style3:
+		g := style3.NewGrid(i.size, uint32(sum))
+		d.Lines = g.Rows()
+		d.Fold = g.Fold()
+	case Style4:
+		g := i.truchet(data)
+		d.Lines = g.Angles()
+		d.Tile = g.Tile().String()
+	case Style5:
+		d.Lines = i.maze(data).Rows()
+	case Style7:
+		m := style7.NewMandala(i.size, uint32(sum))
+		d.Fold = m.N()
+		for _, motif := range m.Motifs() {
+			d.Lines = append(d.Lines, motif.String())
+		}
+	default:
+		panic("无效的 style")
+	}
+
+	return d
+}
+
+// FormatRows 将 Rows 转换为由 0 和 1 组成的字符串
+//
+// 每个元素表示一行，从左到右依次为各个点，1 表示前景色，0 表示背景色。
+func (d *Description) FormatRows() []string {
+	rows := make([]string, 0, len(d.Rows))
+	for _, row := range d.Rows {
+		rows = append(rows, formatRow(uint64(row), len(d.Rows)))
+	}
+	return rows
+}
+
+// 将 row 的低 width 位格式化为二进制字符串
+func formatRow(row uint64, width int) string {
+	s := strconv.FormatUint(row, 2)
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"encoding/json"
+	"image"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_Describe(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := S1(size)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style1).
+		Equal(d.Sum, ii.sum([]byte("192.168.1.1"))).
+		NotNil(d.Corner).
+		NotNil(d.Edge).
+		NotNil(d.Center).
+		Equal(d.Symmetry, "rotate").
+		Nil(d.Blocks).
+		Nil(d.Rows)
+	p := ii.Make([]byte("192.168.1.1")).(*image.Paletted)
+	a.Equal(p.Palette[1], ii.foreColors[d.Color])
+
+	data, err := json.Marshal(d)
+	a.NotError(err).
+		Contains(string(data), `"corner":{"index":`).
+		NotContains(string(data), `"rows"`)
+	d2 := &Description{}
+	a.NotError(json.Unmarshal(data, d2)).Equal(d2, d)
+
+	// 其它对称方式给出各个位置的方块
+	ii = S1(size).SetSymmetry(SymmetryNone)
+	d = ii.Describe([]byte("192.168.1.1"))
+	a.Equal(d.Symmetry, "none").
+		Length(d.Blocks, 8).
+		Equal(d.Blocks[0], d.Corner).
+		Equal(d.Blocks[1], d.Edge)
+	data, err = json.Marshal(d)
+	a.NotError(err).
+		Contains(string(data), `"symmetry":"none"`).
+		Contains(string(data), `"blocks":[{"index":`)
+
+	// style2 的每一行与图像一致
+	ii = S2(size)
+	for i := 0; i < 10; i++ {
+		data := []byte("describe-" + strconv.Itoa(i))
+		d = ii.Describe(data)
+		a.Nil(d.Corner).Length(d.Rows, 8).Nil(d.Lines)
+
+		p := ii.Make(data).(*image.Paletted)
+		a.Equal(p.Palette[1], ii.foreColors[d.Color])
+		rows := d.FormatRows()
+		a.Length(rows, 8)
+		for y, row := range rows {
+			a.Length(row, 8).True(d.Rows[y] < 1<<8)
+			for x, c := range row {
+				px := x*ii.bitsPerPoint + ii.bitsPerPoint/2
+				py := y*ii.bitsPerPoint + ii.bitsPerPoint/2
+				a.Equal(p.ColorIndexAt(px, py), uint8(c-'0'), "x=%d,y=%d", x, y).
+					Equal(uint8(d.Rows[y]>>(7-x)&1), uint8(c-'0'), "x=%d,y=%d", x, y)
+			}
+		}
+	}
+
+	// 以数值的形式序列化
+	d = S2(size).Describe([]byte("192.168.1.1"))
+	data, err = json.Marshal(d)
+	a.NotError(err).Contains(string(data), `"rows":[`+strconv.Itoa(int(d.Rows[0]))+`,`)
+	d2 = &Description{}
+	a.NotError(json.Unmarshal(data, d2)).Equal(d2, d)
+}
+
+func TestIdenticon_Describe_style3(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style3, size, back, fore)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style3).
+		Nil(d.Corner).
+		Length(d.Lines, 6).
+		Nil(d.Rows).
+		True(d.Fold == 6 || d.Fold == 3)
+
+	data, err := json.Marshal(d)
+	a.NotError(err).Contains(string(data), `"fold":`)
+}
+
+func TestIdenticon_Describe_style4(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style4, size, back, fore)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style4).
+		Length(d.Lines, 6).
+		Length(d.Lines[0], 6).
+		NotEmpty(d.Tile).
+		Equal(d.Fold, 0)
+}
+
+func TestIdenticon_Describe_style5(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style5, size, back, fore)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style5).
+		Length(d.Lines, 13).
+		Equal(d.Lines[0], "1011111111111").
+		Empty(d.Tile)
+}
+
+func TestIdenticon_Describe_style6(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style6, size, back, fore)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style6).
+		Length(d.Rows, 16).
+		Length(d.FormatRows()[0], 16).
+		NotEqual(d.Rule, 0)
+
+	d2 := ii.SetGrid(8).SetAsymmetric(true).Describe([]byte("192.168.1.1"))
+	a.Length(d2.Rows, 8).NotEqual(d2.Rule, 0)
+}
+
+func TestIdenticon_Describe_style7(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style7, size, back, fore)
+	d := ii.Describe([]byte("192.168.1.1"))
+	a.NotNil(d).
+		Equal(d.Style, Style7).
+		Length(d.Lines, 4).
+		Empty(d.Tile).
+		Equal(d.Rule, 0)
+	a.True(d.Fold == 5 || d.Fold == 6 || d.Fold == 8 || d.Fold == 12)
+}
+
+func TestFormatRow(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(formatRow(0, 8), "00000000").
+		Equal(formatRow(0b1001, 8), "00001001").
+		Equal(formatRow(0b1100_0011, 8), "11000011").
+		Equal(formatRow(0b101, 3), "101")
+}
@@ -9,7 +9,7 @@
 // 进行 hash 运算，之后根据 hash 数据，产生一张图像，
 // 这样即可以为用户产生一张独特的头像，又不会泄漏用户的隐藏。
 //
-// 提供了两种风格的头像：Style1 和 Style2。
+// 提供了以下几种风格的头像：Style1 至 Style7。
 //
 // style1
 //
@@ -31,6 +31,41 @@
 //
 // 将用户内容计算出 32 位的 hash 值，以 4 bit 为一行，
 // 值为 1 表示有前景色，为 0 表示没有背景色，同时镜像到右边。
+// 默认为 8x8 的点阵，可以通过 NewGrid 或是 Identicon.SetGrid 修改。
+//
+// style3
+//
+// 在图像中间绘制一个正六边形，并按三角形网格将其分成 54 个小三角形，
+// 根据 hash 值决定每个三角形是否填充前景色，整体呈 6 重或 3 重旋转对称。
+//
+// style4
+//
+// 将图像分成 6x6 个 Truchet 图块，所有图块采用相同的图案(圆弧、斜线或是三角形)，
+// 根据 hash 值决定每个图块旋转的角度，相邻图块的线条首尾相接，形成连续的图案。
+//
+// style5
+//
+// 以 hash 值作为随机数的种子，生成一个 6x6 的迷宫，墙壁为前景色。
+// 入口位于左上角，出口位于右下角，任意两个格子之间有且只有一条通路。
+//
+// style6
+//
+// 由 hash 值选择一维初等元胞自动机的规则以及第一行的内容，之后的每一行都由上一行演化而来，
+// 默认为 16x16 的点阵，与 style2 相同，可以修改点阵的大小、形状以及是否镜像。
+//
+// style7
+//
+// 由 4 个同心的圆环组成的曼陀罗图案，每个圆环平均分成 5、6、8 或 12 个扇区，
+// 由 hash 值决定扇区的数量以及每个圆环的图案，整体呈旋转对称，且左右对称。
+// 光栅化时只采用整数运算，在任何平台上都会生成完全相同的图片。
+//
+// 版本
+//
+// 每种风格的算法都带有版本号，比如 Style1V1、Style1V2，其中 Style1 即 Style1V1，
+// Style3 至 Style7 目前均只有 V1 版本，即 Style3V1 至 Style7V1。
+// 各个风格的版本相互独立，可以通过 Style.Version 获取。
+// 已经发布的版本，对于相同的输入，始终会生成完全相同的图片，
+// 算法的改进只会以新版本的形式发布。
 //
 //	// 根据用户访问的 IP ，为其生成一张头像
 //	img := identicon.Make(Style2, 128, color.NRGBA{},color.NRGBA{}, []byte("192.168.1.1"))
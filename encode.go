@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sync"
+
+	"github.com/issue9/identicon/v2/internal/bmp"
+)
+
+// Format 图片的格式
+type Format int8
+
+const (
+	FormatPNG  Format = iota // PNG 格式，采用 1 位的调色板。
+	FormatSVG                // SVG 格式
+	FormatGIF                // GIF 格式，支持透明的背景色。
+	FormatJPEG               // JPEG 格式，透明的颜色会与白色混合。
+	FormatBMP                // BMP 格式，采用 1 位的调色板，透明的颜色会与白色混合。
+)
+
+// Encoder 将头像编码为指定的格式
+type Encoder interface {
+	// ContentType 编码之后内容的 MIME 类型
+	ContentType() string
+
+	// Encode 将 i 根据 data 生成的头像编码之后写入 w
+	Encode(w io.Writer, i *Identicon, data []byte) error
+}
+
+type encoder struct {
+	name        string
+	contentType string
+	encode      func(io.Writer, *Identicon, []byte) error
+}
+
+// 缓存编码时所用的内存
+var (
+	bufferPool = &sync.Pool{New: func() interface{} { return &bytes.Buffer{} }}
+	pngEncoder = &png.Encoder{BufferPool: &pngPool{}}
+)
+
+const maxPooledBuffer = 1 << 20 // 超过此大小的 bytes.Buffer 不再放回 bufferPool
+
+// 将 buf 放回 bufferPool，超过 maxPooledBuffer 的直接丢弃。
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
+
+type pngPool struct{ p sync.Pool }
+
+func (p *pngPool) Get() *png.EncoderBuffer {
+	if b := p.p.Get(); b != nil {
+		return b.(*png.EncoderBuffer)
+	}
+	return nil
+}
+
+func (p *pngPool) Put(b *png.EncoderBuffer) { p.p.Put(b) }
+
+var encoders = []*encoder{
+	FormatPNG: {name: "png", contentType: "image/png", encode: func(w io.Writer, i *Identicon, data []byte) error {
+		return pngEncoder.Encode(w, i.Make(data))
+	}},
+	FormatSVG: {name: "svg", contentType: "image/svg+xml", encode: func(w io.Writer, i *Identicon, data []byte) error {
+		return i.WriteSVG(w, data)
+	}},
+	FormatGIF: {name: "gif", contentType: "image/gif", encode: func(w io.Writer, i *Identicon, data []byte) error {
+		return gif.Encode(w, i.Make(data), nil)
+	}},
+	FormatJPEG: {name: "jpeg", contentType: "image/jpeg", encode: func(w io.Writer, i *Identicon, data []byte) error {
+		return jpeg.Encode(w, flatten(i.Make(data).(*image.Paletted)), &jpeg.Options{Quality: 90})
+	}},
+	FormatBMP: {name: "bmp", contentType: "image/bmp", encode: func(w io.Writer, i *Identicon, data []byte) error {
+		return bmp.Encode(w, flatten(i.Make(data).(*image.Paletted)))
+	}},
+}
+
+func (e *encoder) ContentType() string { return e.contentType }
+
+func (e *encoder) Encode(w io.Writer, i *Identicon, data []byte) error { return e.encode(w, i, data) }
+
+// 将调色板中的颜色与白色混合，去掉透明度。
+//
+// 返回的图片与 p 共用像素。
+func flatten(p *image.Paletted) *image.Paletted {
+	palette := make(color.Palette, 0, len(p.Palette))
+	for _, c := range p.Palette {
+		r, g, b, a := c.RGBA() // 预乘之后的值
+		white := 0xffff - a
+		palette = append(palette, color.RGBA64{R: uint16(r + white), G: uint16(g + white), B: uint16(b + white), A: 0xffff})
+	}
+
+	return &image.Paletted{Pix: p.Pix, Stride: p.Stride, Rect: p.Rect, Palette: palette}
+}
+
+// ParseFormat 将格式的名称转换为 Format
+//
+// name 可以是 png、svg、gif、jpeg(或 jpg) 和 bmp。
+func ParseFormat(name string) (Format, error) {
+	if name == "jpg" {
+		return FormatJPEG, nil
+	}
+	for f, e := range encoders {
+		if e.name == name {
+			return Format(f), nil
+		}
+	}
+	return 0, fmt.Errorf("无效的格式 %s", name)
+}
+
+// IsValid 是否为有效的值
+func (f Format) IsValid() bool { return f >= 0 && int(f) < len(encoders) }
+
+func (f Format) String() string {
+	if !f.IsValid() {
+		return "<unknown>"
+	}
+	return encoders[f].name
+}
+
+// ContentType 该格式对应的 MIME 类型
+func (f Format) ContentType() string { return f.Encoder().ContentType() }
+
+// Encoder 该格式对应的 Encoder
+func (f Format) Encoder() Encoder {
+	if !f.IsValid() {
+		panic(fmt.Sprintf("无效的格式 %d", f))
+	}
+	return encoders[f]
+}
+
+// Write 将根据 data 生成的头像以 format 格式写入 w
+//
+// 编码时采用缓存的内存，编码完成之后才一次性写入 w，编码失败时不会向 w 写入任何内容。
+// 对应的 MIME 类型可以通过 format.ContentType() 获取。
+func (i *Identicon) Write(w io.Writer, data []byte, format Format) error {
+	e := format.Encoder()
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer putBuffer(buf)
+
+	if err := e.Encode(buf, i, data); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
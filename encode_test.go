@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestFormat(t *testing.T) {
+	a := assert.New(t, false)
+
+	for name, ct := range map[string]string{
+		"png":  "image/png",
+		"svg":  "image/svg+xml",
+		"gif":  "image/gif",
+		"jpeg": "image/jpeg",
+		"bmp":  "image/bmp",
+	} {
+		f, err := ParseFormat(name)
+		a.NotError(err).
+			True(f.IsValid()).
+			Equal(f.String(), name).
+			Equal(f.ContentType(), ct).
+			Equal(f.Encoder().ContentType(), ct)
+	}
+
+	f, err := ParseFormat("jpg")
+	a.NotError(err).Equal(f, FormatJPEG)
+
+	_, err = ParseFormat("webp")
+	a.Error(err)
+
+	f = Format(100)
+	a.False(f.IsValid()).Equal(f.String(), "<unknown>")
+	a.Panic(func() {
+		f.Encoder()
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Transparent, color.NRGBA{R: 255, A: 255}, color.NRGBA{A: 128}})
+	f := flatten(p)
+	a.Equal(f.Pix, p.Pix).
+		Equal(color.RGBAModel.Convert(f.Palette[0]), color.RGBA{R: 255, G: 255, B: 255, A: 255}).
+		Equal(color.RGBAModel.Convert(f.Palette[1]), color.RGBA{R: 255, A: 255}).
+		Equal(color.RGBAModel.Convert(f.Palette[2]), color.RGBA{R: 127, G: 127, B: 127, A: 255})
+}
+
+func TestIdenticon_Write(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2V2, size, color.Transparent, fore)
+	data := []byte("192.168.1.1")
+	pix := ii.Make(data).(*image.Paletted).Pix
+
+	for f := FormatPNG; f <= FormatBMP; f++ {
+		buf := &bytes.Buffer{}
+		a.NotError(ii.Write(buf, data, f), f)
+
+		switch f {
+		case FormatPNG:
+			img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+			a.NotError(err).Equal(img.(*image.Paletted).Pix, pix)
+		case FormatSVG:
+			a.NotError(xml.Unmarshal(buf.Bytes(), &struct{}{}))
+		case FormatGIF:
+			img, err := gif.Decode(bytes.NewReader(buf.Bytes()))
+			a.NotError(err).Equal(img.(*image.Paletted).Pix, pix)
+			_, _, _, alpha := img.At(0, 0).RGBA()
+			a.Equal(alpha, 0) // 透明的背景色
+		case FormatJPEG:
+			img, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+			a.NotError(err).Equal(img.Bounds(), image.Rect(0, 0, size, size))
+			r, g, b, _ := img.At(0, 0).RGBA()
+			a.True(r > 0xf000 && g > 0xf000 && b > 0xf000) // 透明的背景色转换为白色
+		case FormatBMP:
+			a.Equal(buf.Bytes()[:2], []byte("BM")).
+				Equal(buf.Bytes()[28], 1) // 1 位的像素
+		}
+
+		fi, err := os.Create("./testdata/write." + f.String())
+		a.NotError(err).NotNil(fi)
+		_, err = buf.WriteTo(fi)
+		a.NotError(err)
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	// 多次调用，缓存的内容不会相互影响。
+	b1, b2 := &bytes.Buffer{}, &bytes.Buffer{}
+	a.NotError(ii.Write(b1, data, FormatPNG))
+	a.NotError(ii.Write(b2, []byte("other"), FormatPNG))
+	a.NotError(ii.Write(b2, data, FormatPNG))
+	a.True(bytes.HasSuffix(b2.Bytes(), b1.Bytes()))
+
+	a.Panic(func() {
+		ii.Write(b1, data, 100)
+	})
+}
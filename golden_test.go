@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color/palette"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 已发布的各个版本的输出，任何修改都不应该改变这些值。
+//
+// fore 为前景色在 palette.WebSafe 中的下标；
+// pix 为 image.Paletted.Pix 的 sha256 值。
+var goldens = []struct {
+	style Style
+	size  int
+	data  string
+	fore  int
+	pix   string
+}{
+	{style: Style1V1, size: 48, data: "", fore: 0, pix: "acb64db97a6987fb5910a16f00522a1c904a1ff332f63c4bfd9df34e218c54b5"},
+	{style: Style1V1, size: 48, data: "192.168.1.1", fore: 112, pix: "78212e26717dad32f5ebe7b689763234a92256d332532c0cd3248c2830d7dfb0"},
+	{style: Style1V1, size: 48, data: "caixw@example.com", fore: 80, pix: "574e90a53044e048eefcc2b7023d67d5d2102898dfb6d521096361c1a31e4cdc"},
+	{style: Style1V1, size: 48, data: "identicon", fore: 56, pix: "5fe14a152c41065133d9e403e46fe25a777b70b7fc2ed011e91178c24a015dff"},
+	{style: Style1V1, size: 128, data: "", fore: 0, pix: "aff65e7dedf2eae653c6d715c0bb7aa2946d904acf1b43ed76d017e9da144055"},
+	{style: Style1V1, size: 128, data: "192.168.1.1", fore: 112, pix: "39fe500ea9bca37a89c53947919db8ae469518eb2ea6ca47eb7d7a9e3f00e551"},
+	{style: Style1V1, size: 128, data: "caixw@example.com", fore: 80, pix: "c046fbbde601f56ee83668be0dfd2786621bc8ca69b35d0245a7bdb945b3e266"},
+	{style: Style1V1, size: 128, data: "identicon", fore: 56, pix: "0171530142272d84eecc2ea85c6c84b43c6fe55b093562192c871eefe7979247"},
+	{style: Style2V1, size: 48, data: "", fore: 0, pix: "bcda106f40f16dc0d3a2f2a88600fafef3095cfc8e5472b497e645217c0e0938"},
+	{style: Style2V1, size: 48, data: "192.168.1.1", fore: 112, pix: "3388320a3b070edc103faff3cd3527abda558ffcaf4cb0380daaae47e0fda26d"},
+	{style: Style2V1, size: 48, data: "caixw@example.com", fore: 80, pix: "e6947aa973621d400a121a64559e924b6536abea62908f162a7b0420e73daa0f"},
+	{style: Style2V1, size: 48, data: "identicon", fore: 56, pix: "23a8e8cf253730378c27fa785838c2c8f0dc8d47cf9b05e44af3a4fc789716a7"},
+	{style: Style2V1, size: 128, data: "", fore: 0, pix: "72471bb42d687f3674e170530fb0000f9bdda8d36f74d6b52c77ef7a53ee5c60"},
+	{style: Style2V1, size: 128, data: "192.168.1.1", fore: 112, pix: "e7088eb310eadcd6dcf3d1a33e13845aa009c5980b2da51ef5d7d985470a026c"},
+	{style: Style2V1, size: 128, data: "caixw@example.com", fore: 80, pix: "fe24d09b2c6c503cc338055c0f205511f74182c95e39953d7fe5c43f7321cd76"},
+	{style: Style2V1, size: 128, data: "identicon", fore: 56, pix: "99a719ee31620653c87b8de414ccafdd3861507b22f7a45b706d9e97dca5e054"},
+	{style: Style1V2, size: 48, data: "", fore: 148, pix: "9c05f46d42b9f266b519486a04e245f9bb3291b93cdbcd7d2340b825cdf1e2c3"},
+	{style: Style1V2, size: 48, data: "192.168.1.1", fore: 72, pix: "2b28695bf8fcf2aad8c4d2451808c8caef1f7a795b2c83736382f7fa4be64e8f"},
+	{style: Style1V2, size: 48, data: "caixw@example.com", fore: 157, pix: "2687c14a443f6a511964b0dacc43e262ac30089e815f2ff8abf4345a3edfb6fc"},
+	{style: Style1V2, size: 48, data: "identicon", fore: 153, pix: "8f04af06ca1c87833467fb94be5219b65627a69b86ddf0007cf4b6d16861913c"},
+	{style: Style1V2, size: 128, data: "", fore: 148, pix: "9dd4524c952e560d8cf79c34f4e20565ff8c9aa8d2c79cacd44189abbbc67059"},
+	{style: Style1V2, size: 128, data: "192.168.1.1", fore: 72, pix: "312b85705ca774831a73ad15b54df2041bf923179157df81ee4eb84079265961"},
+	{style: Style1V2, size: 128, data: "caixw@example.com", fore: 157, pix: "e370a96bcdbd11b72fdad1842571e162a3ca1e47d8c8e2b5e5b21ce99dd0dc1b"},
+	{style: Style1V2, size: 128, data: "identicon", fore: 153, pix: "7deb8c50c751eb71297d043af6b981b564baf18bd46e097681a2c58ecdb0bcb6"},
+	{style: Style2V2, size: 48, data: "", fore: 148, pix: "683ff9d8758580c0e06c330b96e0306abf6e159f93a00fa6128a021954c8805f"},
+	{style: Style2V2, size: 48, data: "192.168.1.1", fore: 72, pix: "2baf5db26ab6896308c435195368123c92ae5e9994761c2abf550c9477aa6a86"},
+	{style: Style2V2, size: 48, data: "caixw@example.com", fore: 157, pix: "5037c26241807c94c50783ece1906287242d4b6f00a973c9ae518c76d9292a80"},
+	{style: Style2V2, size: 48, data: "identicon", fore: 153, pix: "cadeef52fb87a1e1123a597df3e15423e49c61753ead201fffe3e1b1f6bfd43f"},
+	{style: Style2V2, size: 128, data: "", fore: 148, pix: "f18d679c262432ea3763c72297bb98041418bc6db3cf53dcff902634bdf1af9b"},
+	{style: Style2V2, size: 128, data: "192.168.1.1", fore: 72, pix: "7b4946539b3f47c13cfa2cce3cffdcd96c8fede9385485e48d3030b30b286b33"},
+	{style: Style2V2, size: 128, data: "caixw@example.com", fore: 157, pix: "376fb606f79dd2c6515f2b57c16cdbc4b89138be5a9c0ea93039b0a1fe8948cd"},
+	{style: Style2V2, size: 128, data: "identicon", fore: 153, pix: "6d2e25902f3b82eb0eb70445aa85759c50862d151bd59e50a77ddbddc80b2e2c"},
+	{style: Style3V1, size: 48, data: "", fore: 148, pix: "da343c1a8e279b23588a94f125d41f4e9dd8b56c1147ead353fef2b7b7584f85"},
+	{style: Style3V1, size: 48, data: "192.168.1.1", fore: 72, pix: "7d31420443d8273b9549e2d0030135d8cf5a8f5fe5b5b18395808e337ce893e3"},
+	{style: Style3V1, size: 48, data: "caixw@example.com", fore: 157, pix: "ebee9c2724b7d9d5bd358704a3c11c416b29aae459ad8d5da88062f39a868f40"},
+	{style: Style3V1, size: 48, data: "identicon", fore: 153, pix: "ee7b10b07bee07374223d04d8e99957a0ca4e4272ed5ce3c1a746aa076baf7ad"},
+	{style: Style3V1, size: 128, data: "", fore: 148, pix: "b3864446e0e6e7b6f731897560c3f9a76eb9e92a504d6b07ba40e955c3a9adcd"},
+	{style: Style3V1, size: 128, data: "192.168.1.1", fore: 72, pix: "2527929a3463af6a058add2b12773f0743c99b716257beb0d4d1fd1ee726de34"},
+	{style: Style3V1, size: 128, data: "caixw@example.com", fore: 157, pix: "4ed3cc34d95bdc82986f8a2fa7439c18566e387bb03d1619ce607553fd03d34b"},
+	{style: Style3V1, size: 128, data: "identicon", fore: 153, pix: "975f01452237e4f857f4560d9575b917070a3ce33ef14322b0b512b19d995e16"},
+	{style: Style4V1, size: 48, data: "", fore: 148, pix: "f79d1218348708fbd3b908802d61e534df686d6b2eec8f7144fdd90dfd594c1f"},
+	{style: Style4V1, size: 48, data: "192.168.1.1", fore: 72, pix: "1d28eea0d046e8e641083077f0d8620c4bb8cda01ca0cd9cc48910263045595a"},
+	{style: Style4V1, size: 48, data: "caixw@example.com", fore: 157, pix: "e0129e783c2b81b6208ccebb3ae6504db5ba88c4e550b97ad8abd34b48a80ff5"},
+	{style: Style4V1, size: 48, data: "identicon", fore: 153, pix: "29fbc2f41fddd1cd8c909a1153a9cf9bc99f9d191f1ef8a9c431fcca4d31fb74"},
+	{style: Style4V1, size: 128, data: "", fore: 148, pix: "960ba817359e5d7ee670027407f2e8edaa908f240c717f06aa634a331b15e73b"},
+	{style: Style4V1, size: 128, data: "192.168.1.1", fore: 72, pix: "2f1b8debd43c0ce13c82b6f1066118ddc1703862284e586b742a080e4b5a9542"},
+	{style: Style4V1, size: 128, data: "caixw@example.com", fore: 157, pix: "4c3908e78f2224a150834aeb364b306f26da2a94fe75b8510297b21a79d1b2b0"},
+	{style: Style4V1, size: 128, data: "identicon", fore: 153, pix: "d64d983e1e108760d34a840dce62263a0dea73a502ddf8dcf5457cfc4066b01f"},
+	{style: Style5V1, size: 48, data: "", fore: 148, pix: "3ebfc62fc76850508a744ec059714a4fdbf43ebd17619bbc08a70d4876f8bb54"},
+	{style: Style5V1, size: 48, data: "192.168.1.1", fore: 72, pix: "2fe27579f8f9a5b354341dc9c161b7481ce4b936c736335ad8f91f8379d62634"},
+	{style: Style5V1, size: 48, data: "caixw@example.com", fore: 157, pix: "d8fbd19ab25168f9d753e76ae17ffd723e789f9f02a0810986ed91bbcabd1d62"},
+	{style: Style5V1, size: 48, data: "identicon", fore: 153, pix: "af87afe6b1201ab11ac05a07b133739394a3e673790c27fe860d651596f1b36c"},
+	{style: Style5V1, size: 128, data: "", fore: 148, pix: "cdc904b14a3525b9de6bf75ce66de1a8231bfb86cb70c6d087fbeba5534abbcd"},
+	{style: Style5V1, size: 128, data: "192.168.1.1", fore: 72, pix: "091b862763f78276317f161ea65619ce6439babf1df8e3995d4bfbe58719f698"},
+	{style: Style5V1, size: 128, data: "caixw@example.com", fore: 157, pix: "90ea1a3b8324d3742b2a4f0f30f210fbf7db6824a1dace3be304fcbb2078f5c2"},
+	{style: Style5V1, size: 128, data: "identicon", fore: 153, pix: "bbec750c737f8ccaddd0657ca3f457cf099ded60ac349c733a39d36e33acc4b1"},
+	{style: Style6V1, size: 48, data: "", fore: 148, pix: "1fa2567d54e397dd755afafb146755ada3f685c597615535ed66bd5783f10b86"},
+	{style: Style6V1, size: 48, data: "192.168.1.1", fore: 72, pix: "2fbac8d0158ed603e58a57dceab7c9cb4e1506b572b96f9c4f60771874b362aa"},
+	{style: Style6V1, size: 48, data: "caixw@example.com", fore: 157, pix: "95f81b95eb1badc816747b58aa108a9df796979152f28e8ae4e8a0074b48964e"},
+	{style: Style6V1, size: 48, data: "identicon", fore: 153, pix: "254b5d49d4d5041cc9b20e678695787f9b02acf2f19c154562ecc1c7d19d4a17"},
+	{style: Style6V1, size: 128, data: "", fore: 148, pix: "81f13323dcf1614a156ecdcf60d54de8f4d72ceb1ed46ee69c3dcc54d41af2a9"},
+	{style: Style6V1, size: 128, data: "192.168.1.1", fore: 72, pix: "af6d923ba83f863c318b2c04927c5488e1ac161ccb18fa9d815030591577c0ca"},
+	{style: Style6V1, size: 128, data: "caixw@example.com", fore: 157, pix: "447e236cb4644fd1c63007a9a60253320b21855c693967174ed08b2ffe16fc4b"},
+	{style: Style6V1, size: 128, data: "identicon", fore: 153, pix: "755d27a7a94908d0e7398da42c23db3f2d7b6653fc4c2b36a917b69f7f7d885b"},
+	{style: Style7V1, size: 48, data: "", fore: 148, pix: "51c0d0d5b315738e19da7af5c4e7495c921fde8dbb61004342154e19af4e35c4"},
+	{style: Style7V1, size: 48, data: "192.168.1.1", fore: 72, pix: "a849901f2df86b2d25016ed4c5af11431656a3312fe7a5994639e8469bd5e04a"},
+	{style: Style7V1, size: 48, data: "caixw@example.com", fore: 157, pix: "a25bd7fc978ad777e8b5bd279b34f9a7f866083a040f9ab8aadf9d0a0b33280c"},
+	{style: Style7V1, size: 48, data: "identicon", fore: 153, pix: "0bda676307b8f4841ca6c1a0f00fdf7ecaf4eb2c2f55a6851726dc53274c99d0"},
+	{style: Style7V1, size: 128, data: "", fore: 148, pix: "0f523af9c6c24fd7903ea2b6ec3e4168de524d452e29a2e836ed3b4e42b98f33"},
+	{style: Style7V1, size: 128, data: "192.168.1.1", fore: 72, pix: "b8d2fcae9a8df8e15a963ca7478d7258cbe0d3967a496f02ed724040afab1416"},
+	{style: Style7V1, size: 128, data: "caixw@example.com", fore: 157, pix: "3178e8edda86bcac12b4a36be91a35ee4ed8bf8e5fdaa98cce5edbb3a3dca6c1"},
+	{style: Style7V1, size: 128, data: "identicon", fore: 153, pix: "0ae6cbb9a85e22eedc4494915b03870341d8a41dc00fef636779cf06f1976084"},
+}
+
+// 已发布的各个版本在非默认选项下的输出，任何修改都不应该改变这些值。
+//
+// pix 为 image.Paletted.Pix 的 sha256 值。
+var optionGoldens = []struct {
+	name string
+	ii   *Identicon
+	data string
+	pix  string
+}{
+	{name: "s1v2-rotate", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryRotate), data: "192.168.1.1", pix: "312b85705ca774831a73ad15b54df2041bf923179157df81ee4eb84079265961"},
+	{name: "s1v2-rotate", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryRotate), data: "caixw@example.com", pix: "e370a96bcdbd11b72fdad1842571e162a3ca1e47d8c8e2b5e5b21ce99dd0dc1b"},
+	{name: "s1v2-mirror-x", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryMirrorX), data: "192.168.1.1", pix: "cc3b7976efd61cdbf925360bd77d1c2666e595b392297a9c42080a82741cf30e"},
+	{name: "s1v2-mirror-x", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryMirrorX), data: "caixw@example.com", pix: "5261f16a9c6f4474b1e93bfac00432d1ad5c494ba16e8560dccb882637d51d44"},
+	{name: "s1v2-mirror-xy", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryMirrorXY), data: "192.168.1.1", pix: "9eb021e9b2760a5ef132f83569e9d9cc55c9998c809e813b5fd08f25f6c4a559"},
+	{name: "s1v2-mirror-xy", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryMirrorXY), data: "caixw@example.com", pix: "5fc89ee5136940ff89db839ac359da5f7cdf82531e70a3aa7514e40e46e7a2c2"},
+	{name: "s1v2-diagonal", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryDiagonal), data: "192.168.1.1", pix: "689532f63e1d9056b3337740a4e3d30eda1f5e4687399cda16b3e3bda212e64e"},
+	{name: "s1v2-diagonal", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryDiagonal), data: "caixw@example.com", pix: "5bdaac0c3ce340073be875964f4a69de1f56c3cac4287a72199f741326b98920"},
+	{name: "s1v2-none", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryNone), data: "192.168.1.1", pix: "e1d1bc4a69f9b585187b7ab5aba2fb8854a6093c4a7e611de0223e3c6e4ee2cd"},
+	{name: "s1v2-none", ii: New(Style1V2, size, back, palette.WebSafe...).SetSymmetry(SymmetryNone), data: "caixw@example.com", pix: "faa38fdeaa1c9456bc6f907f87fb3363485011fbc723f54f2f7dfab4a45c84ed"},
+	{name: "s2v2-grid-5", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(5), data: "192.168.1.1", pix: "b574a0e1e86e8c85e372978816e245e52984221595e2591374f73d612f45b91c"},
+	{name: "s2v2-grid-5", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(5), data: "caixw@example.com", pix: "9721b053fed9a0c9e9f30cdd6c34ae7be665260983f2a302fb417b931a5bfae4"},
+	{name: "s2v2-grid-6", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(6), data: "192.168.1.1", pix: "f355e66de2dba00de7253ea6fe227cdf4be6aa5fc1951aff4e488d5dc666822d"},
+	{name: "s2v2-grid-6", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(6), data: "caixw@example.com", pix: "d638480b1692d4aa4301151ec44354b37bd454ae6bd791f9c2c542c900483fc8"},
+	{name: "s2v2-grid-10", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(10), data: "192.168.1.1", pix: "4166230148b4fcc89789d230c8450604c3b149299d4827f4dcfc3846ab63fa9d"},
+	{name: "s2v2-grid-10", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(10), data: "caixw@example.com", pix: "2ca1740553d1743ad8da7d81f77a658cc6998c349084cb435b33632379e2ac71"},
+	{name: "s2v2-grid-12", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(12), data: "192.168.1.1", pix: "839470ecd35ff69c44249deb18df5b204ce4a4c7f5d100d79fd9d18b35c517d4"},
+	{name: "s2v2-grid-12", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(12), data: "caixw@example.com", pix: "b83d0c8efc37d31f60c79e4c2d890412947deb2079bb8a157174684818f8587a"},
+	{name: "s2v2-grid-16", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(16), data: "192.168.1.1", pix: "587b4f2c01cb4e9ac07cb4054c2300d2168baccc433f9bce4e13414389f21d91"},
+	{name: "s2v2-grid-16", ii: New(Style2V2, 240, back, palette.WebSafe...).SetGrid(16), data: "caixw@example.com", pix: "e0ee93f1cd0e069f9cf69e960862c645d1a3e6e542146f61f09dfc21ffa272fc"},
+	{name: "s2v2-square", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeSquare, 2), data: "192.168.1.1", pix: "2e5b2f58969d5ff06a0929e1289f7db5036dfbf9cf5cae6ae5675cb2584ba8a5"},
+	{name: "s2v2-square", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeSquare, 2), data: "caixw@example.com", pix: "a40e3675f420b83a337c68f1476bc081668bc98cf77da137d4776a55c001efc7"},
+	{name: "s2v2-circle", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeCircle, 2), data: "192.168.1.1", pix: "cfcca8742cc526f9e362f27b22d261f2438edbb20e8c92daa43482e5263ffa5f"},
+	{name: "s2v2-circle", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeCircle, 2), data: "caixw@example.com", pix: "f6b0e13fe70529e762eef34c6f80660f73123585fe9f47e729927cb019b5b617"},
+	{name: "s2v2-rounded-square", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeRoundedSquare, 2), data: "192.168.1.1", pix: "f2fd10502fe18d7b26fff9b35bb2803b248f03e6a3de6ccf6aa68cd3a287251f"},
+	{name: "s2v2-rounded-square", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeRoundedSquare, 2), data: "caixw@example.com", pix: "d11341d2f07769be24add9007f79aabfc4ed8deb75b37d9d50deafd67cfb0077"},
+	{name: "s2v2-diamond", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeDiamond, 2), data: "192.168.1.1", pix: "9d47df31440baaf38293839475cbeec1f9f6136a7f7f1ff428f68758cc909c0b"},
+	{name: "s2v2-diamond", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeDiamond, 2), data: "caixw@example.com", pix: "40920455dfc9a6fec5369124c2a55ae02b9e585e26a18d5aee9156f3ac8525cc"},
+	{name: "s2v2-hexagon", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeHexagon, 2), data: "192.168.1.1", pix: "f54ab83307681d8a39232f3c43056611e299e4e606012507ba1f24082080e283"},
+	{name: "s2v2-hexagon", ii: New(Style2V2, size, back, palette.WebSafe...).SetShape(ShapeHexagon, 2), data: "caixw@example.com", pix: "73e4ab8f8bf3b94fccdffe88dba499df750246c60fad7e60ee6e33f4ca0f311f"},
+	{name: "s2v2-asymmetric", ii: New(Style2V2, size, back, palette.WebSafe...).SetAsymmetric(true), data: "192.168.1.1", pix: "dc3324a5ccc8db0bcf3f1f29e432602de78900f8a735017fad58fe6cc6cc7aba"},
+	{name: "s2v2-asymmetric", ii: New(Style2V2, size, back, palette.WebSafe...).SetAsymmetric(true), data: "caixw@example.com", pix: "34c50145e2df6a66b76047e1e6b19b17279f3dfa3f066df2b0c720439efa08c0"},
+	{name: "s2v2-asymmetric-16", ii: New(Style2V2, size, back, palette.WebSafe...).SetGrid(16).SetAsymmetric(true), data: "192.168.1.1", pix: "43d85954e7b14dd6396cd75bff440f002520d66bd9545330affdc79c890ce02f"},
+	{name: "s2v2-asymmetric-16", ii: New(Style2V2, size, back, palette.WebSafe...).SetGrid(16).SetAsymmetric(true), data: "caixw@example.com", pix: "cf82a022979a149e3d44dc3dacb4db10f7de08f3fbe25258ea9aeca0783704b3"},
+}
+
+func TestGoldens(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, g := range goldens {
+		ii := New(g.style, g.size, back, palette.WebSafe...)
+		p, ok := ii.Make([]byte(g.data)).(*image.Paletted)
+		a.True(ok)
+
+		sum := sha256.Sum256(p.Pix)
+		a.Equal(hex.EncodeToString(sum[:]), g.pix, "style=%d,size=%d,data=%s", g.style, g.size, g.data).
+			Equal(p.Palette[1], palette.WebSafe[g.fore], "style=%d,size=%d,data=%s", g.style, g.size, g.data)
+
+		// 预渲染和按需计算的结果也应该相同
+		a.Equal(ii.Precompute().Make([]byte(g.data)), p)
+		lazy := image.NewPaletted(p.Rect, p.Palette)
+		l := ii.MakeLazy([]byte(g.data))
+		for y := 0; y < g.size; y++ {
+			for x := 0; x < g.size; x++ {
+				lazy.SetColorIndex(x, y, l.ColorIndexAt(x, y))
+			}
+		}
+		a.Equal(lazy, p)
+	}
+}
+
+func TestOptionGoldens(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, g := range optionGoldens {
+		p, ok := g.ii.Make([]byte(g.data)).(*image.Paletted)
+		a.True(ok)
+
+		sum := sha256.Sum256(p.Pix)
+		a.Equal(hex.EncodeToString(sum[:]), g.pix, "name=%s,data=%s", g.name, g.data)
+	}
+}
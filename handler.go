@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// 协商时格式的优先顺序
+//
+// 报头中的权重相同时，精确匹配的格式优先，之后按此顺序选择。
+var negotiateFormats = []Format{FormatPNG, FormatSVG, FormatGIF, FormatJPEG, FormatBMP}
+
+// Accept 报头中的一项
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+type discardStore struct{}
+
+func (discardStore) Get(string) ([]byte, error) { return nil, fs.ErrNotExist }
+
+func (discardStore) Put(string, []byte) error { return nil }
+
+// Handler 将 Identicon 包装为 http.Handler
+//
+// 以请求路径的最后一段作为生成头像的数据，比如 /avatar/caixw.svg 中的 caixw。
+// 带有 .svg、.png、.gif 等扩展名时，直接采用该扩展名对应的格式，否则根据 Accept 报头进行协商，
+// 此时会输出 Vary: Accept 报头。协商时没有可接受的格式，比如只接受 image/webp，则采用 PNG。
+//
+// 生成的内容通过 Fetch 从 store 中获取，store 为 nil 时每次都重新生成。
+// 每种格式都有各自的 ETag，匹配 If-None-Match 时返回 304 且不会生成头像。
+// 只接受 GET 和 HEAD 请求。
+func (i *Identicon) Handler(store Store) http.Handler {
+	if store == nil {
+		store = discardStore{}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		key, format, explicit := parseAvatarPath(r.URL.Path)
+		if key == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if !explicit {
+			format = negotiate(r.Header.Get("Accept"))
+			w.Header().Add("Vary", "Accept")
+		}
+
+		data := []byte(key)
+		name := i.StoreName(data, format)
+		etag := `"` + name[strings.LastIndexByte(name, '/')+1:] + `"`
+
+		if matchETag(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		content, err := i.Fetch(store, data, format)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("ETag", etag) // 仅在内容生成成功之后才输出，以免缓存保存了并不存在的内容的 ETag。
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if r.Method != http.MethodHead {
+			w.Write(content)
+		}
+	})
+}
+
+// 从请求路径中提取数据和格式
+//
+// explicit 表示路径中是否带有有效的扩展名。
+func parseAvatarPath(p string) (key string, format Format, explicit bool) {
+	if p == "" || p[len(p)-1] == '/' {
+		return "", 0, false
+	}
+
+	key = path.Base(p)
+	if ext := path.Ext(key); len(ext) > 1 {
+		if f, err := ParseFormat(strings.ToLower(ext[1:])); err == nil {
+			return key[:len(key)-len(ext)], f, true
+		}
+	}
+	return key, 0, false
+}
+
+// 根据 Accept 报头选择格式
+func negotiate(accept string) Format {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return FormatPNG
+	}
+
+	best, bestQ, bestExact := FormatPNG, 0.0, false
+	for _, f := range negotiateFormats {
+		q, exact := quality(ranges, f.ContentType())
+		if q > bestQ || (q == bestQ && q > 0 && exact && !bestExact) {
+			best, bestQ, bestExact = f, q, exact
+		}
+	}
+	return best
+}
+
+func parseAccept(accept string) []*acceptRange {
+	var ranges []*acceptRange
+	for _, item := range strings.Split(accept, ",") {
+		params := strings.Split(item, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		index := strings.IndexByte(mediaRange, '/')
+		if index <= 0 || index == len(mediaRange)-1 {
+			continue
+		}
+
+		ar := &acceptRange{typ: mediaRange[:index], subtype: mediaRange[index+1:], q: 1}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q >= 0 && q <= 1 {
+					ar.q = q
+				}
+			}
+		}
+		ranges = append(ranges, ar)
+	}
+	return ranges
+}
+
+// 返回 contentType 在 ranges 中的权重
+//
+// 采用最精确匹配的项，exact 表示是否为完全匹配。
+func quality(ranges []*acceptRange, contentType string) (q float64, exact bool) {
+	index := strings.IndexByte(contentType, '/')
+	typ, subtype := contentType[:index], contentType[index+1:]
+
+	level := -1 // 0 表示 */*，1 表示 type/*，2 表示完全匹配。
+	for _, ar := range ranges {
+		l := -1
+		switch {
+		case ar.typ == typ && ar.subtype == subtype:
+			l = 2
+		case ar.typ == typ && ar.subtype == "*":
+			l = 1
+		case ar.typ == "*" && ar.subtype == "*":
+			l = 0
+		}
+		if l > level {
+			level, q = l, ar.q
+		}
+	}
+	return q, level == 2
+}
+
+// If-None-Match 报头是否与 etag 匹配
+func matchETag(header, etag string) bool {
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.TrimPrefix(item, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestParseAvatarPath(t *testing.T) {
+	a := assert.New(t, false)
+
+	test := func(p, key string, format Format, explicit bool) {
+		t.Helper()
+		k, f, e := parseAvatarPath(p)
+		a.Equal(k, key, p).Equal(e, explicit, p)
+		if explicit {
+			a.Equal(f, format, p)
+		}
+	}
+
+	test("/avatar/caixw.svg", "caixw", FormatSVG, true)
+	test("/avatar/caixw.PNG", "caixw", FormatPNG, true)
+	test("/avatar/caixw.jpg", "caixw", FormatJPEG, true)
+	test("/caixw.gif", "caixw", FormatGIF, true)
+	test("/avatar/caixw", "caixw", 0, false)
+	test("/avatar/john.doe", "john.doe", 0, false)
+	test("/avatar/caixw.webp", "caixw.webp", 0, false)
+	test("/avatar/.svg", "", FormatSVG, true)
+	test("/avatar/", "", 0, false)
+	test("", "", 0, false)
+}
+
+func TestNegotiate(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(negotiate(""), FormatPNG).
+		Equal(negotiate("*/*"), FormatPNG).
+		Equal(negotiate("image/*"), FormatPNG).
+		Equal(negotiate("image/svg+xml"), FormatSVG).
+		Equal(negotiate("image/png;q=0.5, image/svg+xml"), FormatSVG).
+		Equal(negotiate("image/svg+xml;q=0.5, image/png"), FormatPNG).
+		Equal(negotiate("image/svg+xml;q=0, */*"), FormatPNG).
+		Equal(negotiate("image/gif, image/png;q=0.9"), FormatGIF).
+		Equal(negotiate("text/html"), FormatPNG).
+		Equal(negotiate("image/webp"), FormatPNG).
+		Equal(negotiate("invalid, /, image/"), FormatPNG)
+
+	// 浏览器
+	a.Equal(negotiate("image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8"), FormatSVG).
+		Equal(negotiate("image/avif,image/webp,*/*"), FormatPNG)
+}
+
+func TestMatchETag(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.True(matchETag(`"abc"`, `"abc"`)).
+		True(matchETag(`"x", W/"abc"`, `"abc"`)).
+		True(matchETag(`*`, `"abc"`)).
+		False(matchETag(``, `"abc"`)).
+		False(matchETag(`"abcd"`, `"abc"`))
+}
+
+func TestIdenticon_Handler(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2, size, back, fore)
+	s := &countStore{Store: MemoryStore()}
+	h := ii.Handler(s)
+
+	do := func(method, target string, header ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		for k := 0; k < len(header); k += 2 {
+			r.Header.Set(header[k], header[k+1])
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	svg := &bytes.Buffer{}
+	a.NotError(ii.Write(svg, []byte("caixw"), FormatSVG))
+	png := &bytes.Buffer{}
+	a.NotError(ii.Write(png, []byte("caixw"), FormatPNG))
+
+	// 根据 Accept 协商
+	w := do(http.MethodGet, "/avatar/caixw", "Accept", "image/svg+xml,image/*")
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "image/svg+xml").
+		Equal(w.Header().Get("Vary"), "Accept").
+		Equal(w.Body.Bytes(), svg.Bytes())
+	svgETag := w.Header().Get("ETag")
+	a.NotEmpty(svgETag)
+
+	w = do(http.MethodGet, "/avatar/caixw")
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "image/png").
+		Equal(w.Header().Get("Vary"), "Accept").
+		Equal(w.Body.Bytes(), png.Bytes())
+	pngETag := w.Header().Get("ETag")
+	a.NotEmpty(pngETag).NotEqual(pngETag, svgETag)
+
+	// 扩展名优先于 Accept
+	w = do(http.MethodGet, "/avatar/caixw.png", "Accept", "image/svg+xml")
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "image/png").
+		Empty(w.Header().Get("Vary")).
+		Equal(w.Header().Get("ETag"), pngETag).
+		Equal(w.Body.Bytes(), png.Bytes())
+
+	w = do(http.MethodGet, "/avatar/caixw.gif")
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "image/gif").
+		NotEqual(w.Header().Get("ETag"), pngETag)
+
+	// 已经生成的内容不会重复生成
+	a.Equal(s.puts, 3)
+
+	// If-None-Match
+	gets := s.gets
+	w = do(http.MethodGet, "/avatar/caixw.svg", "If-None-Match", svgETag)
+	a.Equal(w.Code, http.StatusNotModified).
+		Equal(w.Header().Get("ETag"), svgETag).
+		Equal(w.Body.Len(), 0).
+		Equal(s.gets, gets)
+
+	w = do(http.MethodGet, "/avatar/caixw.svg", "If-None-Match", pngETag)
+	a.Equal(w.Code, http.StatusOK).Equal(w.Body.Bytes(), svg.Bytes())
+
+	// HEAD
+	w = do(http.MethodHead, "/avatar/caixw.svg")
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Length"), strconv.Itoa(svg.Len())).
+		Equal(w.Body.Len(), 0)
+
+	// 无效的请求
+	a.Equal(do(http.MethodPost, "/avatar/caixw").Code, http.StatusMethodNotAllowed).
+		Equal(do(http.MethodGet, "/avatar/").Code, http.StatusNotFound)
+
+	// store 返回错误，不会输出 ETag。
+	w = httptest.NewRecorder()
+	ii.Handler(&failStore{get: errors.New("get")}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/caixw", nil))
+	a.Equal(w.Code, http.StatusInternalServerError).Empty(w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	ii.Handler(&failStore{get: fs.ErrNotExist, put: errors.New("put")}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/caixw.svg", nil))
+	a.Equal(w.Code, http.StatusInternalServerError).Empty(w.Header().Get("ETag"))
+
+	// 未指定 store
+	w = httptest.NewRecorder()
+	ii.Handler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/caixw.svg", nil))
+	a.Equal(w.Code, http.StatusOK).Equal(w.Body.Bytes(), svg.Bytes())
+}
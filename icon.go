@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/issue9/identicon/v2/internal/style3"
+	"github.com/issue9/identicon/v2/internal/style4"
+	"github.com/issue9/identicon/v2/internal/style5"
+	"github.com/issue9/identicon/v2/internal/style7"
+)
+
+// ICOSizes WriteICO 中包含的尺寸
+var ICOSizes = []int{16, 32, 48, 64}
+
+// Style1 直接绘制时的最小尺寸
+//
+// 此时每个方块为 4 像素，方块中最小的 1/4 单位正好为 1 像素，比 style1.MinSize 更小的尺寸依然能够完整地绘制所有方块。
+const minStyle1Render = 12
+
+// 图标集合中的 PNG 文件
+var pngIcons = []struct {
+	name     string
+	size     int
+	manifest bool // 是否出现在 manifest.json 中
+}{
+	{name: "apple-touch-icon.png", size: 180},
+	{name: "icon-192.png", size: 192, manifest: true},
+	{name: "icon-512.png", size: 512, manifest: true},
+}
+
+// MakeSize 生成尺寸为 size 的头像
+//
+// 与 Make(data) 的图案和颜色相同，但是会以 size 重新绘制，而不是缩放 Make 生成的图片：
+// Style1 会以能被 6 整除的尺寸绘制，保证每个方块以及方块的中点都对齐到像素，
+// 即使小于 New 所允许的最小尺寸，也会直接绘制，比如 16 会以 12 绘制，每个方块 4 像素；
+// Style2 和 Style6 会以点阵行数的整数倍绘制；
+// Style7 超过其最大尺寸时，以最大尺寸绘制；
+// 多余的部分以背景色填充，图案位于图片的中间。
+// 如果 size 小于风格可以绘制的最小尺寸，则以最小尺寸绘制，再按像素的中心点取样缩小到 size。
+func (i *Identicon) MakeSize(data []byte, size int) image.Image {
+	if size <= 0 {
+		panic(fmt.Sprintf("参数 size 的值 %d 必须大于 0", size))
+	}
+	if size == i.size {
+		return i.Make(data)
+	}
+
+	render := i.resize(size)
+	src := render.Make(data).(*image.Paletted)
+	if render.size == size {
+		return src
+	}
+
+	dst := image.NewPaletted(image.Rect(0, 0, size, size), src.Palette)
+	if render.size < size {
+		offset := (size - render.size) / 2
+		draw.Draw(dst, src.Rect.Add(image.Pt(offset, offset)), src, image.Point{}, draw.Src)
+		return dst
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dst.SetColorIndex(x, y, src.ColorIndexAt((2*x+1)*render.size/(2*size), (2*y+1)*render.size/(2*size)))
+		}
+	}
+	return dst
+}
+
+// 返回以 size 为基准重新绘制所用的 Identicon
+//
+// 返回对象的尺寸是各风格在不超过 size 时可以整齐绘制的最大尺寸，但不会小于风格的最小尺寸，也不会超过风格的最大尺寸。
+func (i *Identicon) resize(size int) *Identicon {
+	render := size
+	switch i.style.base() {
+	case Style1:
+		render -= render % 6
+		if render < minStyle1Render {
+			render = minStyle1Render
+		}
+	case Style2, Style6:
+		render -= render % i.blocks
+		if render < i.blocks {
+			render = i.blocks
+		}
+	case Style3:
+		if render < style3.MinSize {
+			render = style3.MinSize
+		}
+	case Style4:
+		if render < style4.MinSize {
+			render = style4.MinSize
+		}
+	case Style5:
+		if render < style5.MinSize {
+			render = style5.MinSize
+		}
+	case Style7:
+		if render < style7.MinSize {
+			render = style7.MinSize
+		} else if render > style7.MaxSize {
+			render = style7.MaxSize
+		}
+	}
+
+	ii := *i
+	ii.size = render
+	ii.rect = image.Rect(0, 0, render, render)
+	ii.bitsPerPoint = render / ii.blocks
+	ii.gap = i.gap * render / i.size
+	ii.masks = nil // 预先渲染的方块仅适用于原来的尺寸，而重新绘制通常只有一次，并不值得重新渲染。
+	return &ii
+}
+
+// WriteICO 将 data 生成的头像以 ICO 格式写入 w
+//
+// 包含 ICOSizes 中的所有尺寸，每个尺寸都由 MakeSize 单独绘制，并以 PNG 的格式保存在 ICO 文件中。
+func (i *Identicon) WriteICO(w io.Writer, data []byte) error {
+	frames := make([][]byte, 0, len(ICOSizes))
+	for _, size := range ICOSizes {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, i.MakeSize(data, size)); err != nil {
+			return err
+		}
+		frames = append(frames, buf.Bytes())
+	}
+	return writeICO(w, ICOSizes, frames)
+}
+
+// 将 PNG 格式的 frames 写入 ICO 文件
+func writeICO(w io.Writer, sizes []int, frames [][]byte) error {
+	const headerSize, entrySize = 6, 16
+
+	buf := &bytes.Buffer{}
+	header := [3]uint16{0, 1, uint16(len(frames))} // 保留字段、类型(1 表示图标)和图片的数量
+	if err := binary.Write(buf, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	offset := headerSize + entrySize*len(frames)
+	for index, frame := range frames {
+		size := sizes[index]
+		if size >= 256 { // 0 表示 256
+			size = 0
+		}
+
+		entry := struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Bytes, Offset                   uint32
+		}{
+			Width:    uint8(size),
+			Height:   uint8(size),
+			Planes:   1,
+			BitCount: pngBitCount(frame),
+			Bytes:    uint32(len(frame)),
+			Offset:   uint32(offset),
+		}
+		if err := binary.Write(buf, binary.LittleEndian, entry); err != nil {
+			return err
+		}
+		offset += len(frame)
+	}
+
+	for _, frame := range frames {
+		buf.Write(frame)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// 根据 PNG 的 IHDR 计算每个像素的位数
+//
+// 调色板中的颜色数量较少时，png.Encode 会采用 1、2 或 4 位的位深度，
+// ICO 中记录的 BitCount 需要与之相符；无法识别时返回 0，表示由 PNG 本身决定。
+func pngBitCount(frame []byte) uint16 {
+	// 8 字节的签名之后是 IHDR，依次为长度、类型、宽、高、位深度和颜色类型。
+	if len(frame) < 26 || string(frame[12:16]) != "IHDR" {
+		return 0
+	}
+
+	var channels uint16
+	switch frame[25] {
+	case 0, 3: // 灰度和调色板
+		channels = 1
+	case 2: // RGB
+		channels = 3
+	case 4: // 带透明度的灰度
+		channels = 2
+	case 6: // RGBA
+		channels = 4
+	default:
+		return 0
+	}
+	return uint16(frame[24]) * channels
+}
+
+// IconSet 生成网站和 PWA 所需的一组图标
+//
+// 返回值的键名为文件名，键值为文件的内容，包含以下文件：
+//   - favicon.ico：由 WriteICO 生成；
+//   - apple-touch-icon.png：180 像素；
+//   - icon-192.png 和 icon-512.png：192 和 512 像素；
+//   - manifest.json：包含以上两个 PNG 文件的 icons 字段，可合并到网站的 manifest.json 中；
+//
+// 每个尺寸都由 MakeSize 单独绘制。
+func (i *Identicon) IconSet(data []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(pngIcons)+2)
+
+	buf := &bytes.Buffer{}
+	if err := i.WriteICO(buf, data); err != nil {
+		return nil, err
+	}
+	files["favicon.ico"] = buf.Bytes()
+
+	type manifestIcon struct {
+		Src   string `json:"src"`
+		Sizes string `json:"sizes"`
+		Type  string `json:"type"`
+	}
+	manifest := struct {
+		Icons []manifestIcon `json:"icons"`
+	}{}
+
+	for _, icon := range pngIcons {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, i.MakeSize(data, icon.size)); err != nil {
+			return nil, err
+		}
+		files[icon.name] = buf.Bytes()
+
+		if icon.manifest {
+			s := strconv.Itoa(icon.size)
+			manifest.Icons = append(manifest.Icons, manifestIcon{Src: icon.name, Sizes: s + "x" + s, Type: "image/png"})
+		}
+	}
+
+	m, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files["manifest.json"] = m
+
+	return files, nil
+}
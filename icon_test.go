@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_MakeSize(t *testing.T) {
+	a := assert.New(t, false)
+	data := []byte("192.168.1.1")
+
+	items := []*Identicon{
+		S1(size),
+		New(Style2V2, size, back, fore).SetShape(ShapeCircle, 2),
+		New(Style3, size, back, fore),
+		New(Style4, size, back, fore),
+		New(Style5, size, back, fore),
+		New(Style6, size, back, fore),
+		New(Style7, size, back, fore),
+	}
+	for _, ii := range items {
+		a.Equal(ii.MakeSize(data, size), ii.Make(data))
+
+		for _, s := range []int{16, 32, 48, 64, 180, 192, 512} {
+			img := ii.MakeSize(data, s).(*image.Paletted)
+			a.Equal(img.Bounds(), image.Rect(0, 0, s, s)).
+				Equal(img.Palette, ii.Make(data).(*image.Paletted).Palette)
+		}
+	}
+
+	// 64 以 60 绘制，四周各留出 2 像素。
+	ii := New(Style1V2, size, back, fore)
+	img := ii.MakeSize(data, 64).(*image.Paletted)
+	expected := New(Style1V2, 60, back, fore).Make(data).(*image.Paletted)
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if x < 2 || y < 2 || x >= 62 || y >= 62 {
+				a.Equal(img.ColorIndexAt(x, y), 0)
+			} else {
+				a.Equal(img.ColorIndexAt(x, y), expected.ColorIndexAt(x-2, y-2))
+			}
+		}
+	}
+
+	// 16 小于 style1.MinSize，但依然直接以 12 绘制，四周各留出 2 像素，而不是缩小。
+	r := ii.resize(16)
+	a.Equal(r.size, 12)
+	img = ii.MakeSize(data, 16).(*image.Paletted)
+	expected = r.Make(data).(*image.Paletted)
+	var count int
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			if x < 2 || y < 2 || x >= 14 || y >= 14 {
+				a.Equal(img.ColorIndexAt(x, y), 0)
+			} else {
+				a.Equal(img.ColorIndexAt(x, y), expected.ColorIndexAt(x-2, y-2))
+				count += int(img.ColorIndexAt(x, y))
+			}
+		}
+	}
+	a.True(count > 0)
+	a.Equal(ii.resize(8).size, 12) // 更小的尺寸才会缩小
+
+	// 重新绘制时不会重新渲染方块
+	ii = New(Style1V2, size, back, fore).Precompute()
+	a.NotNil(ii.masks).
+		Nil(ii.resize(64).masks).
+		Equal(ii.MakeSize(data, 64), New(Style1V2, size, back, fore).MakeSize(data, 64))
+
+	// Style2 以点阵行数的整数倍绘制，同时缩放间隔。
+	ii = New(Style2V2, size, back, fore).SetShape(ShapeSquare, 4)
+	r = ii.resize(180)
+	a.Equal(r.size, 176).Equal(r.bitsPerPoint, 22).Equal(r.gap, 5)
+
+	a.Panic(func() {
+		ii.MakeSize(data, 0)
+	})
+}
+
+func TestIdenticon_WriteICO(t *testing.T) {
+	a := assert.New(t, false)
+
+	buf := &bytes.Buffer{}
+	a.NotError(S2(size).WriteICO(buf, []byte("192.168.1.1")))
+	ico := buf.Bytes()
+
+	a.Equal(binary.LittleEndian.Uint16(ico[2:]), 1).
+		Equal(binary.LittleEndian.Uint16(ico[4:]), len(ICOSizes))
+	for index, s := range ICOSizes {
+		entry := ico[6+16*index:]
+		a.Equal(entry[0], s).Equal(entry[1], s).
+			Equal(binary.LittleEndian.Uint16(entry[6:]), 1) // 只有前景色和背景色，采用 1 位的调色板。
+
+		length := binary.LittleEndian.Uint32(entry[8:])
+		offset := binary.LittleEndian.Uint32(entry[12:])
+		img, err := png.Decode(bytes.NewReader(ico[offset : offset+length]))
+		a.NotError(err).Equal(img.Bounds(), image.Rect(0, 0, s, s))
+	}
+
+	// 不同颜色数量的 PNG
+	for _, item := range []struct {
+		img  image.Image
+		bits uint16
+	}{
+		{img: S2(size).Make([]byte("192.168.1.1")), bits: 1},
+		{img: image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.White, color.Black, color.Opaque}), bits: 2},
+		{img: image.NewPaletted(image.Rect(0, 0, 2, 2), palette.WebSafe), bits: 8},
+		{img: image.NewNRGBA(image.Rect(0, 0, 2, 2)), bits: 32},
+		{img: image.NewGray(image.Rect(0, 0, 2, 2)), bits: 8},
+	} {
+		b := &bytes.Buffer{}
+		a.NotError(png.Encode(b, item.img))
+		a.Equal(pngBitCount(b.Bytes()), item.bits)
+	}
+	a.Equal(pngBitCount([]byte("not png")), 0)
+
+	fi, err := os.Create("./testdata/favicon.ico")
+	a.NotError(err).NotNil(fi)
+	_, err = buf.WriteTo(fi)
+	a.NotError(err)
+	a.NotError(fi.Close()) // 关闭文件
+}
+
+func TestIdenticon_IconSet(t *testing.T) {
+	a := assert.New(t, false)
+
+	files, err := New(Style7, size, back, fore).IconSet([]byte("192.168.1.1"))
+	a.NotError(err).Length(files, 5).
+		NotEmpty(files["favicon.ico"]).
+		Equal(string(files["manifest.json"]), `{
+  "icons": [
+    {
+      "src": "icon-192.png",
+      "sizes": "192x192",
+      "type": "image/png"
+    },
+    {
+      "src": "icon-512.png",
+      "sizes": "512x512",
+      "type": "image/png"
+    }
+  ]
+}`)
+
+	for name, s := range map[string]int{"apple-touch-icon.png": 180, "icon-192.png": 192, "icon-512.png": 512} {
+		img, err := png.Decode(bytes.NewReader(files[name]))
+		a.NotError(err, name).Equal(img.Bounds(), image.Rect(0, 0, s, s))
+	}
+}
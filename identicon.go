@@ -5,8 +5,9 @@
 package identicon
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
-	"hash"
 	"hash/fnv"
 	"image"
 	"image/color"
@@ -17,13 +18,87 @@ import (
 
 	"github.com/issue9/identicon/v2/internal/style1"
 	"github.com/issue9/identicon/v2/internal/style2"
+	"github.com/issue9/identicon/v2/internal/style3"
+	"github.com/issue9/identicon/v2/internal/style4"
+	"github.com/issue9/identicon/v2/internal/style5"
+	"github.com/issue9/identicon/v2/internal/style6"
+	"github.com/issue9/identicon/v2/internal/style7"
 )
 
+// Style 头像的风格
+//
+// 同一风格的同一版本算法，对于相同的输入始终会产生完全相同的图片。
+// 对算法的改进只会以新版本的形式出现，已经发布的版本不会再有任何改动，
+// 所以需要长期保持头像不变的，应当明确指定带版本号的风格，比如 Style1V1。
 type Style int8
 
 const (
-	Style1 Style = iota + 1 // 旧版本风格
-	Style2                  // Style2 风格，性能略高于 Style1
+	Style1   Style = iota + 1 // 旧版本风格
+	Style2                    // Style2 风格，性能略高于 Style1
+	Style1V2                  // Style1 的 V2 版本，修正了 V1 中图案和颜色分布不均匀的问题
+	Style2V2                  // Style2 的 V2 版本，修正了 V1 中颜色分布不均匀的问题
+	Style3                    // 由三角形组成的六边形，呈 6 重或 3 重旋转对称。
+	Style4                    // 由 Truchet 图块组成的连续曲线或折线
+	Style5                    // 迷宫，任意两个格子之间有且只有一条通路。
+	Style6                    // 一维元胞自动机演化生成的点阵
+	Style7                    // 圆形的曼陀罗图案，呈 5、6、8 或 12 重旋转对称。
+)
+
+const (
+	Style1V1 = Style1 // Style1 的 V1 版本
+	Style2V1 = Style2 // Style2 的 V1 版本
+	Style3V1 = Style3 // Style3 的 V1 版本
+	Style4V1 = Style4 // Style4 的 V1 版本
+	Style5V1 = Style5 // Style5 的 V1 版本
+	Style6V1 = Style6 // Style6 的 V1 版本
+	Style7V1 = Style7 // Style7 的 V1 版本
+)
+
+// 各个风格的名称
+//
+// 作为风格的标识用于文件名等需要长期保存的场合，与 Style 的值无关，已经使用的名称不能再修改。
+var styleNames = map[Style]string{
+	Style1V1: "s1v1",
+	Style2V1: "s2v1",
+	Style1V2: "s1v2",
+	Style2V2: "s2v2",
+	Style3V1: "s3v1",
+	Style4V1: "s4v1",
+	Style5V1: "s5v1",
+	Style6V1: "s6v1",
+	Style7V1: "s7v1",
+}
+
+// Version 风格所采用算法的版本
+//
+// 各个风格的版本相互独立，比如 Style3 的 V1 与 Style1 的 V1 之间并没有关系。
+type Version int8
+
+const (
+	V1 Version = iota + 1
+	V2
+)
+
+// Symmetry Style1 中九个方块的对称方式
+type Symmetry = style1.Symmetry
+
+const (
+	SymmetryRotate   = style1.Rotate   // 四个角和四条边上的方块依次旋转 90 度，默认值。
+	SymmetryMirrorX  = style1.MirrorX  // 左右镜像对称
+	SymmetryMirrorXY = style1.MirrorXY // 上下和左右均镜像对称
+	SymmetryDiagonal = style1.Diagonal // 以左上至右下的对角线镜像对称
+	SymmetryNone     = style1.None     // 不对称，各个位置的方块及其角度互不相关。
+)
+
+// Shape Style2 中点阵的每个点的形状
+type Shape = style2.Shape
+
+const (
+	ShapeSquare        = style2.Square        // 正方形，默认值。
+	ShapeCircle        = style2.Circle        // 圆形
+	ShapeRoundedSquare = style2.RoundedSquare // 圆角正方形
+	ShapeDiamond       = style2.Diamond       // 菱形
+	ShapeHexagon       = style2.Hexagon       // 六边形
 )
 
 // Identicon 用于产生统一尺寸的头像
@@ -35,10 +110,17 @@ type Identicon struct {
 	backColor  color.Color
 	size       int
 	rect       image.Rectangle
-	hash       hash.Hash32
 
-	// style v2
+	// style v1
+	masks    *style1.Masks
+	symmetry Symmetry
+
+	// style v2 和 style6
+	blocks       int
 	bitsPerPoint int
+	shape        Shape
+	gap          int
+	asymmetric   bool
 }
 
 // S1 采用 style1 风格的头像
@@ -61,19 +143,64 @@ func S2(size int) *Identicon {
 // size 头像的大小，应该将 size 的值保持在能被 3 整除的偶数，图片才会平整；
 // back 前景色；
 // fore 所有可能的前景色，会为每个图像随机挑选一个作为其前景色。
+//
+// Style2 和 Style6 的 size 必须能被默认的点阵行数整除，需要其它行数的点阵时，可以采用 NewGrid。
 func New(style Style, size int, back color.Color, fore ...color.Color) *Identicon {
+	blocks := style2.Blocks
+	if style.base() == Style6 {
+		blocks = style6.Blocks
+	}
+	return newIdenticon(style, size, blocks, back, fore)
+}
+
+// NewGrid 声明一个点阵为 blocks 行和列的 Identicon 实例
+//
+// 仅适用于 Style2 和 Style6，其它风格会 panic，blocks 的取值范围为 [4,16]，其它参数与 New 相同。
+// size 只需要能被 blocks 整除，而不必是默认点阵行数的倍数，比如 5×5 的点阵可以采用 60 的尺寸，
+// 而 New(Style2, 60, ...).SetGrid(5) 则会因为 60 不能被 8 整除而失败。
+func NewGrid(style Style, size, blocks int, back color.Color, fore ...color.Color) *Identicon {
+	if b := style.base(); b != Style2 && b != Style6 {
+		panic("NewGrid 仅适用于 Style2 和 Style6")
+	}
+	if blocks < style2.MinBlocks || blocks > style2.MaxBlocks {
+		panic(fmt.Sprintf("参数 blocks 的值 %d 必须介于 [%d,%d] 之间", blocks, style2.MinBlocks, style2.MaxBlocks))
+	}
+	return newIdenticon(style, size, blocks, back, fore)
+}
+
+// blocks 为 Style2 和 Style6 的点阵行数，其它风格忽略该值。
+func newIdenticon(style Style, size, blocks int, back color.Color, fore []color.Color) *Identicon {
+	if !style.IsValid() {
+		panic(fmt.Sprintf("无效的参数 style: %d", style))
+	}
 	if len(fore) == 0 {
 		panic("必须指定 fore 参数")
 	}
 
-	switch style {
+	switch style.base() {
 	case Style1:
 		if size < style1.MinSize {
 			panic(fmt.Sprintf("参数 size 的值 %d 不能小于 %d", size, style1.MinSize))
 		}
-	case Style2:
-		if size%style2.Blocks != 0 {
-			panic(fmt.Sprintf("参数 size 的值 %d 必须为 %d 的倍数", size, style2.Blocks))
+	case Style2, Style6:
+		if size <= 0 || size%blocks != 0 {
+			panic(fmt.Sprintf("参数 size 的值 %d 必须为点阵行数 %d 的倍数", size, blocks))
+		}
+	case Style3:
+		if size < style3.MinSize {
+			panic(fmt.Sprintf("参数 size 的值 %d 不能小于 %d", size, style3.MinSize))
+		}
+	case Style4:
+		if size < style4.MinSize {
+			panic(fmt.Sprintf("参数 size 的值 %d 不能小于 %d", size, style4.MinSize))
+		}
+	case Style5:
+		if size < style5.MinSize {
+			panic(fmt.Sprintf("参数 size 的值 %d 不能小于 %d", size, style5.MinSize))
+		}
+	case Style7:
+		if size < style7.MinSize || size > style7.MaxSize {
+			panic(fmt.Sprintf("参数 size 的值 %d 必须介于 [%d,%d] 之间", size, style7.MinSize, style7.MaxSize))
 		}
 	}
 
@@ -83,10 +210,90 @@ func New(style Style, size int, back color.Color, fore ...color.Color) *Identico
 		backColor:  back,
 		size:       size,
 		rect:       image.Rect(0, 0, size, size),
-		hash:       fnv.New32a(),
 
 		// hash
-		bitsPerPoint: size / style2.Blocks,
+		blocks:       blocks,
+		bitsPerPoint: size / blocks,
+	}
+}
+
+// Precompute 预先渲染所有可能用到的方块
+//
+// 仅对 Style1 有效。对于需要大量调用 Make 的场景，
+// 可以用少量的内存换取 Make 的性能。
+func (i *Identicon) Precompute() *Identicon {
+	if i.style.base() == Style1 && i.masks == nil {
+		i.masks = style1.NewMasks(i.size, i.style.style1Version())
+	}
+	return i
+}
+
+// SetSymmetry 设置 Style1 的对称方式
+//
+// 仅适用于 Style1，其它风格会 panic，默认值为 SymmetryRotate。
+func (i *Identicon) SetSymmetry(s Symmetry) *Identicon {
+	if i.style.base() != Style1 {
+		panic("SetSymmetry 仅适用于 Style1")
+	}
+	if !s.IsValid() {
+		panic(fmt.Sprintf("无效的参数 s: %d", s))
+	}
+	i.symmetry = s
+	return i
+}
+
+// SetGrid 设置 Style2 和 Style6 点阵的行数和列数
+//
+// 仅适用于 Style2 和 Style6，其它风格会 panic，默认值分别为 8 和 16，取值范围为 [4,16]，且头像的大小必须能被 blocks 整除。
+// 由于 New 要求头像的大小能被默认值整除，对于不能被默认值整除的大小，应该直接采用 NewGrid。
+// Style2 的点阵所需的随机位数超过 32 位时，会改用 data 的 SHA-256 值作为随机数的来源。
+func (i *Identicon) SetGrid(blocks int) *Identicon {
+	i.requireGrid("SetGrid")
+	if blocks < style2.MinBlocks || blocks > style2.MaxBlocks {
+		panic(fmt.Sprintf("参数 blocks 的值 %d 必须介于 [%d,%d] 之间", blocks, style2.MinBlocks, style2.MaxBlocks))
+	}
+	if i.size%blocks != 0 {
+		panic(fmt.Sprintf("头像的大小 %d 必须为参数 blocks 的值 %d 的倍数", i.size, blocks))
+	}
+
+	i.blocks = blocks
+	i.bitsPerPoint = i.size / blocks
+	return i
+}
+
+// SetAsymmetric 设置 Style2 和 Style6 的点阵是否为非对称的
+//
+// 仅适用于 Style2 和 Style6，其它风格会 panic，默认为 false，即点阵的右半部分为左半部分的镜像。
+// 为 true 时，点阵中的每个点都由单独的一位随机数决定，
+// 虽然没有镜像那么美观，但是能容纳更多的信息，适合用于密钥指纹等场景。
+func (i *Identicon) SetAsymmetric(asymmetric bool) *Identicon {
+	i.requireGrid("SetAsymmetric")
+	i.asymmetric = asymmetric
+	return i
+}
+
+// SetShape 设置 Style2 和 Style6 点阵中每个点的形状
+//
+// 仅适用于 Style2 和 Style6，其它风格会 panic，默认为 ShapeSquare。
+// gap 为相邻两个点之间间隔的像素，不能小于 0，如果不小于每个点所占的像素，将不会绘制任何内容。
+func (i *Identicon) SetShape(shape Shape, gap int) *Identicon {
+	i.requireGrid("SetShape")
+	if !shape.IsValid() {
+		panic(fmt.Sprintf("无效的参数 shape: %d", shape))
+	}
+	if gap < 0 {
+		panic("参数 gap 不能小于 0")
+	}
+
+	i.shape = shape
+	i.gap = gap
+	return i
+}
+
+// 风格不是 Style2 或 Style6 时 panic，name 为调用的方法名。
+func (i *Identicon) requireGrid(name string) {
+	if b := i.style.base(); b != Style2 && b != Style6 {
+		panic(name + " 仅适用于 Style2 和 Style6")
 	}
 }
 
@@ -98,25 +305,146 @@ func (i *Identicon) Rand(r *rand.Rand) image.Image {
 
 // Make 根据 data 数据随机图片
 func (i *Identicon) Make(data []byte) image.Image {
-	i.hash.Write(data)
-	sum := i.hash.Sum32()
-	i.hash.Reset()
-
-	fc := int(sum&0xf0_f0_f0_f0) % len(i.foreColors)
-	p := image.NewPaletted(i.rect, []color.Color{i.backColor, i.foreColors[fc]})
+	sum := i.sum(data)
+	p := image.NewPaletted(i.rect, i.palette(sum))
 
-	switch i.style {
+	switch i.style.base() {
 	case Style1:
-		style1.DrawBlocks(p, i.size, sum)
+		if i.masks != nil {
+			i.masks.DrawBlocks(p, uint32(sum), i.symmetry)
+		} else {
+			style1.DrawBlocks(p, i.size, uint32(sum), i.style.style1Version(), i.symmetry)
+		}
+		return p
+	case Style2, Style6:
+		i.matrix(data, sum).Draw(p, i.bitsPerPoint, i.shape, i.gap)
+		return p
+	case Style3:
+		style3.NewGrid(i.size, uint32(sum)).Draw(p)
+		return p
+	case Style4:
+		i.truchet(data).Draw(p)
+		return p
+	case Style5:
+		i.maze(data).Draw(p)
 		return p
-	case Style2:
-		style2.Draw(p, i.bitsPerPoint, sum)
+	case Style7:
+		style7.NewMandala(i.size, uint32(sum)).Draw(p)
 		return p
 	default:
 		panic("无效的 style")
 	}
 }
 
+// 计算 data 的 hash 值
+//
+// Style1V1 和 Style2V1 采用 32 位的 FNV-1a；
+// 其它采用 64 位的 FNV-1a，其中低 32 位用于生成图案，高 32 位用于选择前景色。
+func (i *Identicon) sum(data []byte) uint64 {
+	if i.style.legacySum() {
+		h := fnv.New32a()
+		h.Write(data)
+		return uint64(h.Sum32())
+	}
+
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// 生成 Style2 和 Style6 的点阵
+//
+// sum 为 data 的 hash 值，当其位数足够时，直接使用 sum 作为随机数的来源。
+func (i *Identicon) matrix(data []byte, sum uint64) *style2.Matrix {
+	mirror := !i.asymmetric
+	if i.style.base() == Style6 {
+		return style6.New(uint32(sum), i.blocks, mirror).Matrix()
+	}
+
+	if style2.Bits(i.blocks, mirror) <= 32 {
+		return style2.NewMatrix(style2.SumBits(uint32(sum)), i.blocks, mirror)
+	}
+
+	digest := sha256.Sum256(data)
+	return style2.NewMatrix(digest[:], i.blocks, mirror)
+}
+
+// 生成 Style4 的图案
+//
+// 所需的随机位数远超 sum 的位数，所以始终采用 data 的 SHA-256 值作为随机数的来源。
+func (i *Identicon) truchet(data []byte) *style4.Grid {
+	digest := sha256.Sum256(data)
+	return style4.NewGrid(digest[:], i.size)
+}
+
+// 生成 Style5 的迷宫
+//
+// 以 data 的 SHA-256 值的前 8 个字节作为随机数的种子，与选择前景色的 sum 相互独立。
+func (i *Identicon) maze(data []byte) *style5.Maze {
+	digest := sha256.Sum256(data)
+	return style5.NewMaze(binary.LittleEndian.Uint64(digest[:8]), i.size)
+}
+
+// 根据 sum 生成图片的调色板，分别为背景色和前景色。
+func (i *Identicon) palette(sum uint64) color.Palette {
+	return color.Palette{i.backColor, i.foreColors[i.foreIndex(sum)]}
+}
+
+// 根据 sum 选取前景色在 foreColors 中的下标
+func (i *Identicon) foreIndex(sum uint64) int {
+	fc := sum >> 32
+	if i.style.legacySum() {
+		fc = sum & 0xf0_f0_f0_f0
+	}
+	return int(fc % uint64(len(i.foreColors)))
+}
+
+// 风格的基础类型，即不包含版本信息的风格，比如 Style1V2 的基础类型为 Style1。
+func (s Style) base() Style {
+	switch s {
+	case Style1V2:
+		return Style1
+	case Style2V2:
+		return Style2
+	default:
+		return s
+	}
+}
+
+// IsValid 是否为有效的值
+func (s Style) IsValid() bool {
+	_, found := styleNames[s]
+	return found
+}
+
+// Version 风格采用的算法版本
+//
+// 无效的风格返回 0。
+func (s Style) Version() Version {
+	switch {
+	case s == Style1V2 || s == Style2V2:
+		return V2
+	case s.IsValid():
+		return V1
+	default:
+		return 0
+	}
+}
+
+// 是否采用旧的 hash 算法
+//
+// 仅 Style1V1 和 Style2V1 采用 32 位的 FNV-1a 以及旧的前景色选择方式，
+// 其它风格（包括 Style3 及之后各风格的 V1 版本）均采用 64 位的 FNV-1a。
+func (s Style) legacySum() bool { return s == Style1V1 || s == Style2V1 }
+
+// Style1 的版本对应的 style1.Version
+func (s Style) style1Version() style1.Version {
+	if s.Version() == V1 {
+		return style1.V1
+	}
+	return style1.V2
+}
+
 // Make 根据 data 数据产生一张唯一性的头像图片
 //
 // size 头像的大小。
@@ -5,6 +5,7 @@
 package identicon
 
 import (
+	"image"
 	"image/color"
 	"image/png"
 	"math/rand"
@@ -14,6 +15,8 @@ import (
 	"time"
 
 	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/identicon/v2/internal/style7"
 )
 
 var (
@@ -36,6 +39,37 @@ func TestMake(t *testing.T) {
 	}
 }
 
+func TestStyle_Version(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Style1V1.Version(), V1).
+		Equal(Style1V2.Version(), V2).
+		Equal(Style2V1.Version(), V1).
+		Equal(Style2V2.Version(), V2)
+
+	for _, s := range []Style{Style3V1, Style4V1, Style5V1, Style6V1, Style7V1} {
+		a.Equal(s.Version(), V1).False(s.legacySum())
+	}
+
+	a.True(Style1V1.legacySum()).
+		True(Style2V1.legacySum()).
+		False(Style1V2.legacySum()).
+		False(Style2V2.legacySum())
+
+	a.True(Style1V1.IsValid()).
+		True(Style7V1.IsValid()).
+		False(Style(0).IsValid()).
+		False(Style(99).IsValid()).
+		Equal(Style(99).Version(), 0)
+
+	a.PanicString(func() {
+		New(Style(99), size, back, fore)
+	}, "无效的参数 style: 99")
+	a.Panic(func() {
+		NewGrid(Style(0), size, 8, back, fore)
+	})
+}
+
 func TestIdenticon_Make_style1(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -53,6 +87,197 @@ func TestIdenticon_Make_style1(t *testing.T) {
 	}
 }
 
+func TestIdenticon_Precompute(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := S1(size)
+	pre := S1(size).Precompute()
+	a.Equal(pre, pre.Precompute())
+
+	for i := 0; i < 20; i++ {
+		data := []byte("identicon-" + strconv.Itoa(i))
+		a.Equal(ii.Make(data), pre.Make(data))
+	}
+
+	// 对 Style2 无效
+	a.Nil(S2(size).Precompute().masks)
+}
+
+func TestIdenticon_SetSymmetry(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := S1(size)
+	a.Equal(ii.symmetry, SymmetryRotate)
+	data := []byte("symmetry")
+	rotate := ii.Make(data)
+
+	a.Equal(ii.SetSymmetry(SymmetryMirrorX), ii).
+		Equal(ii.symmetry, SymmetryMirrorX).
+		NotEqual(ii.Make(data), rotate).
+		Equal(ii.Make(data), ii.Precompute().Make(data))
+
+	a.Equal(ii.SetSymmetry(SymmetryRotate).Make(data), rotate)
+
+	a.Panic(func() {
+		ii.SetSymmetry(-1)
+	})
+	a.PanicString(func() {
+		S2(size).SetSymmetry(SymmetryMirrorX)
+	}, "SetSymmetry 仅适用于 Style1")
+
+	for s := SymmetryRotate; s <= SymmetryNone; s++ {
+		ii := New(Style1V2, size, back, fore).SetSymmetry(s)
+		img := ii.Make(data)
+
+		fi, err := os.Create("./testdata/s1-symmetry-" + strconv.Itoa(int(s)) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
+
+func TestIdenticon_SetGrid(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := S2(size)
+	data := []byte("grid")
+	img := ii.Make(data)
+	a.Equal(ii.SetGrid(8), ii).Equal(ii.Make(data), img)
+
+	for _, blocks := range []int{5, 6, 7, 10, 12} {
+		ii := New(Style2, 120*7, back, fore).SetGrid(blocks)
+		a.Equal(ii.blocks, blocks).Equal(ii.bitsPerPoint, 120*7/blocks)
+		a.Equal(ii.Make(data), NewGrid(Style2, 120*7, blocks, back, fore).Make(data))
+
+		d := ii.Describe(data)
+		a.Length(d.Rows, blocks).Length(d.FormatRows()[0], blocks)
+
+		img := ii.Make(data)
+		a.Equal(img, ii.Make(data))
+
+		fi, err := os.Create("./testdata/s2-grid-" + strconv.Itoa(blocks) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		S2(size).SetGrid(3)
+	})
+	a.Panic(func() {
+		S2(size).SetGrid(17)
+	})
+	a.Panic(func() {
+		S2(size).SetGrid(5) // 128 不能被 5 整除
+	})
+	a.PanicString(func() {
+		S1(size).SetGrid(8)
+	}, "SetGrid 仅适用于 Style2 和 Style6")
+}
+
+func TestNewGrid(t *testing.T) {
+	a := assert.New(t, false)
+	data := []byte("grid")
+
+	// 60 不能被 8 整除，但可以被 5、6、10 和 12 整除。
+	a.Panic(func() {
+		New(Style2, 60, back, fore)
+	})
+	for _, blocks := range []int{5, 6, 10, 12} {
+		ii := NewGrid(Style2, 60, blocks, back, fore)
+		a.Equal(ii.blocks, blocks).
+			Equal(ii.bitsPerPoint, 60/blocks).
+			Equal(ii.Make(data).Bounds(), image.Rect(0, 0, 60, 60)).
+			Length(ii.Describe(data).Rows, blocks)
+	}
+
+	// Style6 同样可用
+	ii := NewGrid(Style6, 60, 12, back, fore)
+	a.Equal(ii.Make(data).Bounds(), image.Rect(0, 0, 60, 60))
+
+	// 与默认值相同时，与 New 的结果相同。
+	a.Equal(NewGrid(Style2V2, size, 8, back, fore).Make(data), New(Style2V2, size, back, fore).Make(data))
+
+	a.PanicString(func() {
+		NewGrid(Style2, 60, 8, back, fore)
+	}, "参数 size 的值 60 必须为点阵行数 8 的倍数")
+	a.Panic(func() {
+		NewGrid(Style2, 60, 3, back, fore)
+	})
+	a.Panic(func() {
+		NewGrid(Style1, 60, 5, back, fore)
+	})
+	a.Panic(func() {
+		NewGrid(Style2, 60, 5, back)
+	})
+}
+
+func TestIdenticon_SetAsymmetric(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []byte("asymmetric")
+	ii := S2(size)
+	img := ii.Make(data)
+	a.Equal(ii.SetAsymmetric(false).Make(data), img)
+
+	a.Equal(ii.SetAsymmetric(true), ii).NotEqual(ii.Make(data), img)
+	d := ii.Describe(data)
+	var mirrored = true
+	for _, row := range d.FormatRows() {
+		for x := 0; x < len(row)/2; x++ {
+			if row[x] != row[len(row)-1-x] {
+				mirrored = false
+			}
+		}
+	}
+	a.False(mirrored)
+
+	a.PanicString(func() {
+		S1(size).SetAsymmetric(true)
+	}, "SetAsymmetric 仅适用于 Style2 和 Style6")
+
+	// 所有尺寸的点阵都有足够的随机位数
+	for blocks := 4; blocks <= 16; blocks++ {
+		ii := New(Style2V2, blocks*8, back, fore).SetGrid(blocks).SetAsymmetric(true)
+		a.NotNil(ii.Make(data))
+	}
+
+	fi, err := os.Create("./testdata/s2-asymmetric.png")
+	a.NotError(err).NotNil(fi)
+	a.NotError(png.Encode(fi, ii.Make(data)))
+	a.NotError(fi.Close()) // 关闭文件
+}
+
+func TestIdenticon_SetShape(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := S2(size)
+	data := []byte("shape")
+	img := ii.Make(data)
+	a.Equal(ii.SetShape(ShapeSquare, 0), ii).Equal(ii.Make(data), img)
+
+	for s := ShapeSquare; s <= ShapeHexagon; s++ {
+		ii := New(Style2V2, size, back, fore).SetShape(s, 2)
+		a.Equal(ii.shape, s).Equal(ii.gap, 2)
+		img := ii.Make(data)
+
+		fi, err := os.Create("./testdata/s2-shape-" + strconv.Itoa(int(s)) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		S2(size).SetShape(-1, 0)
+	})
+	a.Panic(func() {
+		S2(size).SetShape(ShapeCircle, -1)
+	})
+	a.PanicString(func() {
+		S1(size).SetShape(ShapeCircle, 0)
+	}, "SetShape 仅适用于 Style2 和 Style6")
+}
+
 func TestIdenticon_Rand_style1(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -87,3 +312,126 @@ func TestIdenticon_Make_style2(t *testing.T) {
 		a.NotError(fi.Close()) // 关闭文件
 	}
 }
+
+func TestIdenticon_Make_style3(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style3, size, back, fore)
+	a.NotNil(ii)
+
+	for i := 0; i < 20; i++ {
+		img := ii.Make([]byte("identicon-" + strconv.Itoa(i)))
+		a.NotNil(img)
+
+		fi, err := os.Create("./testdata/s3-identicon-make" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		New(Style3, 27, back, fore)
+	})
+}
+
+func TestIdenticon_Make_style4(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style4, size, back, fore)
+	a.NotNil(ii)
+
+	for i := 0; i < 20; i++ {
+		img := ii.Make([]byte("identicon-" + strconv.Itoa(i)))
+		a.NotNil(img)
+
+		fi, err := os.Create("./testdata/s4-identicon-make" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		New(Style4, 23, back, fore)
+	})
+}
+
+func TestIdenticon_Make_style5(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style5, size, back, fore)
+	a.NotNil(ii)
+
+	for i := 0; i < 20; i++ {
+		img := ii.Make([]byte("identicon-" + strconv.Itoa(i)))
+		a.NotNil(img)
+
+		fi, err := os.Create("./testdata/s5-identicon-make" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		New(Style5, 37, back, fore)
+	})
+}
+
+func TestIdenticon_Make_style6(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style6, size, back, fore)
+	a.NotNil(ii).Equal(ii.blocks, 16).Equal(ii.bitsPerPoint, 8)
+
+	for i := 0; i < 20; i++ {
+		img := ii.Make([]byte("identicon-" + strconv.Itoa(i)))
+		a.NotNil(img)
+
+		fi, err := os.Create("./testdata/s6-identicon-make" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		New(Style6, 100, back, fore)
+	})
+}
+
+func TestIdenticon_Make_style7(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style7, size, back, fore)
+	a.NotNil(ii)
+
+	for i := 0; i < 20; i++ {
+		img := ii.Make([]byte("identicon-" + strconv.Itoa(i)))
+		a.NotNil(img)
+
+		fi, err := os.Create("./testdata/s7-identicon-make" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Panic(func() {
+		New(Style7, 31, back, fore)
+	})
+	a.PanicString(func() {
+		New(Style7, style7.MaxSize+1, back, fore)
+	}, "参数 size 的值 16385 必须介于 [32,16384] 之间")
+
+	// 最大尺寸依然可用，以按需计算的方式检查部分像素。
+	large := New(Style7, style7.MaxSize, back, fore)
+	lazy := large.MakeLazy([]byte("identicon"))
+	small := New(Style7, style7.MaxSize/128, back, fore).Make([]byte("identicon")).(*image.Paletted)
+	var same int
+	for y := 0; y < small.Rect.Dy(); y++ {
+		for x := 0; x < small.Rect.Dx(); x++ {
+			if lazy.ColorIndexAt(x*128+64, y*128+64) == small.ColorIndexAt(x, y) {
+				same++
+			}
+		}
+	}
+	a.True(same > small.Rect.Dx()*small.Rect.Dy()*95/100, same)
+	a.Equal(large.resize(style7.MaxSize*2).size, style7.MaxSize)
+}
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/initials"
+)
+
+// MakeInitials 根据 name 的首字母生成头像
+//
+// 取 name 中第一个和最后一个单词的首字母(只有一个单词时只取一个)，转换为大写之后居中绘制，
+// 带变音符号的拉丁字母以其基本字母绘制，比如 É 绘制为 E；中文的姓名只绘制第一个汉字，即姓氏。
+// 以 key 的 hash 值选取的前景色作为背景，文字为背景色，所选的颜色与 Make(key) 相同。
+// key 通常为用户的 ID 或是邮箱等不会变化的值。
+//
+// 文字采用编译进包中的点阵字体，不依赖系统字体，包含拉丁字母、数字以及 GB2312 的 3755 个一级汉字。
+// name 中没有可用的字符，或是首字母不在字体中(比如生僻字)时，返回 Make(key) 生成的图案。
+func (i *Identicon) MakeInitials(name string, key []byte) image.Image {
+	runes := initials.Pick(name)
+	if len(runes) == 0 {
+		return i.Make(key)
+	}
+	for _, r := range runes {
+		if !initials.Supported(r) {
+			return i.Make(key)
+		}
+	}
+
+	p := image.NewPaletted(i.rect, i.palette(i.sum(key)))
+	initials.New(runes, i.size).Draw(p)
+	return p
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_MakeInitials(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2, size, back, fore)
+	key := []byte("caixw@example.com")
+
+	img := ii.MakeInitials("cai xw", key)
+	a.NotNil(img).Equal(img.Bounds(), image.Rect(0, 0, size, size))
+	p, ok := img.(*image.Paletted)
+	a.True(ok).
+		Equal(p.Palette, ii.Make(key).(*image.Paletted).Palette). // 与 Make 采用相同的颜色
+		Equal(p.ColorIndexAt(0, 0), 1)
+
+	fi, err := os.Create("./testdata/initials.png")
+	a.NotError(err).NotNil(fi)
+	a.NotError(png.Encode(fi, img))
+	a.NotError(fi.Close()) // 关闭文件
+
+	// 中文的姓名绘制姓氏
+	img = ii.MakeInitials("张三", key)
+	a.NotEqual(img, ii.Make(key)).
+		Equal(img, ii.MakeInitials("张", key))
+
+	fi, err = os.Create("./testdata/initials-hanzi.png")
+	a.NotError(err).NotNil(fi)
+	a.NotError(png.Encode(fi, img))
+	a.NotError(fi.Close()) // 关闭文件
+
+	// 带变音符号的字母以基本字母绘制
+	a.Equal(ii.MakeInitials("Émile Zola", key), ii.MakeInitials("emile zola", key))
+
+	// 字体中不存在的字符，返回 Make(key) 的内容。
+	a.Equal(ii.MakeInitials("丌官", key), ii.Make(key)).
+		Equal(ii.MakeInitials(" ", key), ii.Make(key))
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package bmp 将调色板图片编码为 BMP 格式
+package bmp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+const (
+	fileHeaderSize = 14
+	infoHeaderSize = 40
+)
+
+// Encode 将 img 以 BMP 格式写入 w
+//
+// 根据调色板的大小采用 1、4 或 8 位的像素，BMP 不支持透明度，调色板中颜色的透明度会被忽略。
+func Encode(w io.Writer, img *image.Paletted) error {
+	if len(img.Palette) == 0 || len(img.Palette) > 256 {
+		return errors.New("调色板中颜色的数量必须介于 [1,256] 之间")
+	}
+
+	depth := 8
+	switch {
+	case len(img.Palette) <= 2:
+		depth = 1
+	case len(img.Palette) <= 16:
+		depth = 4
+	}
+
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	stride := (width*depth + 31) / 32 * 4 // 每一行都需要对齐到 4 字节
+	offset := fileHeaderSize + infoHeaderSize + 4*len(img.Palette)
+
+	buf := bufio.NewWriter(w)
+	header := struct {
+		// BITMAPFILEHEADER
+		Type     [2]byte
+		FileSize uint32
+		Reserved uint32
+		Offset   uint32
+		// BITMAPINFOHEADER
+		Size             uint32
+		Width, Height    int32
+		Planes, BitCount uint16
+		Compression      uint32
+		ImageSize        uint32
+		XPPM, YPPM       int32
+		Colors, Used     uint32
+	}{
+		Type:      [2]byte{'B', 'M'},
+		FileSize:  uint32(offset + stride*height),
+		Offset:    uint32(offset),
+		Size:      infoHeaderSize,
+		Width:     int32(width),
+		Height:    int32(height), // 正值表示从下往上存储
+		Planes:    1,
+		BitCount:  uint16(depth),
+		ImageSize: uint32(stride * height),
+		XPPM:      2835, // 72 DPI
+		YPPM:      2835,
+		Colors:    uint32(len(img.Palette)),
+	}
+	if err := binary.Write(buf, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	for _, c := range img.Palette {
+		nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+		buf.Write([]byte{nc.B, nc.G, nc.R, 0})
+	}
+
+	row := make([]byte, stride)
+	perByte := 8 / depth
+	for y := img.Rect.Max.Y - 1; y >= img.Rect.Min.Y; y-- {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < width; x++ {
+			shift := uint(8 - depth*(x%perByte+1))
+			row[x/perByte] |= img.ColorIndexAt(img.Rect.Min.X+x, y) << shift
+		}
+		buf.Write(row)
+	}
+
+	return buf.Flush()
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package bmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestEncode(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 3x2，第一行为 1 0 1，第二行为 0 1 1。
+	img := image.NewPaletted(image.Rect(0, 0, 3, 2), color.Palette{color.NRGBA{R: 1, G: 2, B: 3, A: 4}, color.White})
+	img.Pix = []uint8{1, 0, 1, 0, 1, 1}
+
+	buf := &bytes.Buffer{}
+	a.NotError(Encode(buf, img))
+	bs := buf.Bytes()
+	a.Length(bs, 70).
+		Equal(bs[:2], []byte("BM")).
+		Equal(binary.LittleEndian.Uint32(bs[2:]), 70).
+		Equal(binary.LittleEndian.Uint32(bs[10:]), 62).
+		Equal(binary.LittleEndian.Uint32(bs[18:]), 3).
+		Equal(binary.LittleEndian.Uint32(bs[22:]), 2).
+		Equal(binary.LittleEndian.Uint16(bs[28:]), 1).
+		Equal(bs[54:62], []byte{3, 2, 1, 0, 255, 255, 255, 0}). // 调色板
+		Equal(bs[62:], []byte{0b011_00000, 0, 0, 0, 0b101_00000, 0, 0, 0})
+
+	// 4 位，子图片。
+	img = image.NewPaletted(image.Rect(0, 0, 4, 1), color.Palette{color.Black, color.White, color.Transparent})
+	img.Pix = []uint8{0, 1, 2, 1}
+	buf.Reset()
+	a.NotError(Encode(buf, img.SubImage(image.Rect(1, 0, 4, 1)).(*image.Paletted)))
+	bs = buf.Bytes()
+	a.Equal(binary.LittleEndian.Uint16(bs[28:]), 4).
+		Equal(binary.LittleEndian.Uint32(bs[18:]), 3).
+		Equal(bs[14+40+12:], []byte{0x12, 0x10, 0, 0})
+
+	a.Error(Encode(buf, image.NewPaletted(image.Rect(0, 0, 1, 1), nil)))
+}
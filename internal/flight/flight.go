@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package flight 合并相同 key 的并发调用
+//
+// 同一时间内，相同 key 的调用只会执行一次，其它调用等待其完成并共享结果。
+//
+// 功能与 golang.org/x/sync/singleflight 类似，但只需要其中很小的一部分，
+// 为了不引入额外的依赖，并没有直接采用。与其相同，fn 发生 panic 或是调用了 runtime.Goexit 时，
+// 等待结果的调用会返回错误，而不会一直阻塞。
+package flight
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrPanic 执行 fn 时发生了 panic
+	//
+	// 执行 fn 的调用依然会 panic，等待结果的其它调用则返回该错误。
+	ErrPanic = errors.New("flight: 执行时发生了 panic")
+
+	// ErrGoexit 执行 fn 时调用了 runtime.Goexit
+	//
+	// 执行 fn 的 goroutine 依然会退出，等待结果的其它调用则返回该错误。
+	ErrGoexit = errors.New("flight: 执行时调用了 runtime.Goexit")
+)
+
+// Group 合并并发调用的分组
+//
+// 零值即可使用。
+type Group struct {
+	mux   sync.Mutex
+	calls map[interface{}]*call
+}
+
+type call struct {
+	wg      sync.WaitGroup
+	val     []byte
+	err     error
+	waiters int // 等待结果的调用数量，不包含执行 fn 的调用。
+}
+
+// Do 执行 fn 并返回其结果
+//
+// key 必须是可比较的值。如果已经存在相同 key 的调用正在执行，则不再执行 fn，而是等待该调用完成并返回其结果。
+// shared 表示返回值是否同时返回给了多个调用，此时返回的内容不能被修改。
+func (g *Group) Do(key interface{}, fn func() ([]byte, error)) (val []byte, err error, shared bool) {
+	g.mux.Lock()
+	if g.calls == nil {
+		g.calls = make(map[interface{}]*call)
+	}
+	if c, found := g.calls[key]; found {
+		c.waiters++
+		g.mux.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := &call{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mux.Unlock()
+
+	done := false
+	defer func() {
+		var r interface{}
+		if !done {
+			// 发生 panic 时 recover 返回非 nil 值，调用 runtime.Goexit 时返回 nil。
+			if r = recover(); r != nil {
+				c.err = ErrPanic
+			} else {
+				c.err = ErrGoexit
+			}
+		}
+
+		g.mux.Lock()
+		delete(g.calls, key)
+		shared = c.waiters > 0
+		g.mux.Unlock()
+		c.wg.Done()
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	c.val, c.err = fn()
+	done = true
+	return c.val, c.err, false
+}
+
+// Waiters 正在等待 key 对应的调用完成的数量
+//
+// 不存在正在执行的调用时返回 -1。
+func (g *Group) Waiters(key interface{}) int {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	if c, found := g.calls[key]; found {
+		return c.waiters
+	}
+	return -1
+}
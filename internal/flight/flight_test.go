@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package flight
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 等待 key 的调用中有 n 个等待者
+func waitFor(g *Group, key string, n int) {
+	for g.Waiters(key) != n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGroup_Do(t *testing.T) {
+	a := assert.New(t, false)
+	g := &Group{}
+
+	val, err, shared := g.Do("k", func() ([]byte, error) { return []byte("v"), nil })
+	a.NotError(err).Equal(val, []byte("v")).False(shared).
+		Equal(g.Waiters("k"), -1)
+
+	errFn := errors.New("fn")
+	val, err, shared = g.Do("k", func() ([]byte, error) { return nil, errFn })
+	a.ErrorIs(err, errFn).Nil(val).False(shared)
+
+	// 并发调用只执行一次
+	const n = 10
+	var calls int
+	release := make(chan struct{})
+	results := make([][]byte, n)
+	shares := make([]bool, n)
+	wg := &sync.WaitGroup{}
+	do := func(index int) {
+		defer wg.Done()
+		results[index], _, shares[index] = g.Do("k", func() ([]byte, error) {
+			calls++
+			<-release
+			return []byte("v"), nil
+		})
+	}
+
+	wg.Add(1)
+	go do(0)
+	waitFor(g, "k", 0)
+	for index := 1; index < n; index++ {
+		wg.Add(1)
+		go do(index)
+	}
+	waitFor(g, "k", n-1)
+
+	// 其它 key 不受影响
+	val, err, shared = g.Do("other", func() ([]byte, error) { return []byte("o"), nil })
+	a.NotError(err).Equal(val, []byte("o")).False(shared)
+
+	close(release)
+	wg.Wait()
+	a.Equal(calls, 1).Equal(g.Waiters("k"), -1)
+	for index := 0; index < n; index++ {
+		a.Equal(results[index], []byte("v")).True(shares[index])
+	}
+}
+
+func TestGroup_Do_panic(t *testing.T) {
+	a := assert.New(t, false)
+	g := &Group{}
+
+	release := make(chan struct{})
+	go func() {
+		defer func() { recover() }()
+		g.Do("k", func() ([]byte, error) {
+			<-release
+			panic("fn")
+		})
+	}()
+	waitFor(g, "k", 0)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err, _ := g.Do("k", func() ([]byte, error) { return nil, nil })
+		errs <- err
+	}()
+	waitFor(g, "k", 1)
+
+	close(release)
+	a.ErrorIs(<-errs, ErrPanic)
+
+	a.PanicString(func() {
+		g.Do("k", func() ([]byte, error) { panic("fn") })
+	}, "fn")
+	a.Equal(g.Waiters("k"), -1)
+}
+
+func TestGroup_Do_goexit(t *testing.T) {
+	a := assert.New(t, false)
+	g := &Group{}
+
+	release := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		g.Do("k", func() ([]byte, error) {
+			<-release
+			runtime.Goexit()
+			return nil, nil
+		})
+	}()
+	waitFor(g, "k", 0)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err, _ := g.Do("k", func() ([]byte, error) { return nil, nil })
+		errs <- err
+	}()
+	waitFor(g, "k", 1)
+
+	close(release)
+	a.ErrorIs(<-errs, ErrGoexit)
+	<-exited
+	a.Equal(g.Waiters("k"), -1)
+
+	// 之后的调用不受影响
+	val, err, _ := g.Do("k", func() ([]byte, error) { return []byte("v"), nil })
+	a.NotError(err).Equal(val, []byte("v"))
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// 生成 hanzi.bin
+//
+// 由 github.com/hajimehoshi/bitmapfont/v3 中的 FaceSC 绘制 GB2312 的所有一级汉字，
+// 并转换为 initials 包所需的格式。FaceSC 中的汉字来自 Ark Pixel Font 和 Cubic 11，授权见 ../LICENSE-hanzi。
+//
+// 作为独立的模块存在，以免将字体相关的依赖引入到 identicon 中，版本固定在 go.mod 中，
+// 相同的版本始终生成完全相同的 hanzi.bin。在 initials 目录下执行 go generate 即可重新生成。
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"sort"
+
+	"github.com/hajimehoshi/bitmapfont/v3"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 在 16x16 的画布上绘制时，所有汉字的点都位于该区域之内。
+//
+// 宽和高需要与 initials.HanziWidth 和 initials.HanziHeight 相同。
+var crop = image.Rect(0, 2, 12, 13)
+
+func main() {
+	out := flag.String("o", "hanzi.bin", "输出的文件")
+	flag.Parse()
+
+	runes, err := level1()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	data, err := encode(runes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// 返回 GB2312 的一级汉字，按码点从小到大排列。
+//
+// 一级汉字位于 0xB0A1 到 0xD7F9 之间，每个区的第二个字节为 0xA1 到 0xFE。
+func level1() ([]rune, error) {
+	dec := simplifiedchinese.GBK.NewDecoder()
+	runes := make([]rune, 0, 3755)
+	for hi := 0xb0; hi <= 0xd7; hi++ {
+		for lo := 0xa1; lo <= 0xfe; lo++ {
+			if hi == 0xd7 && lo > 0xf9 {
+				break
+			}
+
+			s, err := dec.Bytes([]byte{byte(hi), byte(lo)})
+			if err != nil {
+				return nil, err
+			}
+			r := []rune(string(s))
+			if len(r) != 1 {
+				return nil, fmt.Errorf("无法解码 %X%X", hi, lo)
+			}
+			runes = append(runes, r[0])
+		}
+	}
+
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes, nil
+}
+
+// 每个汉字以大端序的 uint16 保存码点，之后是 crop.Dy() 行，
+// 每行为一个大端序的 uint16，低 crop.Dx() 位从高到低依次表示从左到右的各个点。
+func encode(runes []rune) ([]byte, error) {
+	face := bitmapfont.FaceSC
+	ascent := face.Metrics().Ascent.Ceil()
+
+	data := make([]byte, 0, len(runes)*(2+2*crop.Dy()))
+	for _, r := range runes {
+		img := image.NewAlpha(image.Rect(0, 0, 16, 16))
+		d := &font.Drawer{Dst: img, Src: image.Opaque, Face: face, Dot: fixed.P(0, ascent)}
+		d.DrawString(string(r))
+
+		empty := true
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				if img.AlphaAt(x, y).A > 127 {
+					if !image.Pt(x, y).In(crop) {
+						return nil, fmt.Errorf("%q 超出了 %v 的范围", r, crop)
+					}
+					empty = false
+				}
+			}
+		}
+		if empty {
+			return nil, fmt.Errorf("字体中不存在 %q", r)
+		}
+
+		data = binary.BigEndian.AppendUint16(data, uint16(r))
+		for y := crop.Min.Y; y < crop.Max.Y; y++ {
+			var row uint16
+			for x := crop.Min.X; x < crop.Max.X; x++ {
+				row <<= 1
+				if img.AlphaAt(x, y).A > 127 {
+					row |= 1
+				}
+			}
+			data = binary.BigEndian.AppendUint16(data, row)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package initials
+
+//go:generate go run -C gen . -o ../hanzi.bin
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"sort"
+)
+
+const (
+	HanziWidth  = 12 // 每个汉字的列数
+	HanziHeight = 11 // 每个汉字的行数
+)
+
+// 每个汉字在 hanzi 中占用的字节数
+const hanziRecord = 2 + 2*HanziHeight
+
+// 12px 的汉字点阵
+//
+// 包含 GB2312 的 3755 个一级汉字，由 Ark Pixel Font 和 Cubic 11 转换而来，授权见 LICENSE-hanzi。
+// 按码点从小到大排列，每个汉字以大端序的 uint16 保存码点，之后是 HanziHeight 行，
+// 每行为一个大端序的 uint16，低 HanziWidth 位从高到低依次表示从左到右的各个点。
+// 由 gen 目录下的程序生成，字体的版本固定在其 go.mod 中。
+//
+//go:embed hanzi.bin
+var hanzi []byte
+
+func hanziGlyph(r rune) (*glyph, bool) {
+	n := len(hanzi) / hanziRecord
+	index := sort.Search(n, func(i int) bool {
+		return rune(binary.BigEndian.Uint16(hanzi[i*hanziRecord:])) >= r
+	})
+	if index == n || rune(binary.BigEndian.Uint16(hanzi[index*hanziRecord:])) != r {
+		return nil, false
+	}
+
+	data := hanzi[index*hanziRecord+2 : (index+1)*hanziRecord]
+	g := &glyph{width: HanziWidth, height: HanziHeight, rows: make([]uint16, HanziHeight)}
+	for i := range g.rows {
+		g.rows[i] = binary.BigEndian.Uint16(data[2*i:])
+	}
+	return g, true
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package initials 以内置的点阵字体绘制姓名的首字母
+package initials
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode"
+)
+
+const (
+	GlyphWidth  = 5 // 每个拉丁字母和数字的列数
+	GlyphHeight = 7 // 每个拉丁字母和数字的行数
+	MaxGlyphs   = 2 // 最多的字符数量
+)
+
+// 点阵字符
+type glyph struct {
+	width, height int
+	rows          []uint16 // 每行的低 width 位从高到低依次表示从左到右的各个点
+}
+
+// Text 由点阵字符组成的文字
+type Text struct {
+	runes   []rune
+	glyphs  []*glyph
+	offsets []int // 每个字符起始的列
+	cols    int
+	rows    int
+	unit    int // 点阵中每个点的像素
+	left    int
+	top     int
+}
+
+// Pick 从 name 中提取首字母
+//
+// 以非字母和数字的字符分隔单词，取第一个和最后一个单词的首字母，仅有一个单词时只取一个，
+// 返回值均转换为大写，带变音符号的拉丁字母转换为其基本字母，比如 É 转换为 E。
+// 首字母为汉字时，只取第一个字符，对于中文的姓名即为姓氏(复姓仅取第一个字)。
+// name 中没有任何单词时返回 nil。
+func Pick(name string) []rune {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	f := first(words[0])
+	if len(words) == 1 || unicode.Is(unicode.Han, f) {
+		return []rune{f}
+	}
+
+	l := first(words[len(words)-1])
+	if unicode.Is(unicode.Han, l) {
+		return []rune{f}
+	}
+	return []rune{f, l}
+}
+
+func first(word string) rune {
+	for _, r := range word {
+		r = unicode.ToUpper(r)
+		if base, found := folds[r]; found {
+			return base
+		}
+		return r
+	}
+	return 0
+}
+
+// Supported 字体中是否包含字符 r
+//
+// 包含大写的拉丁字母、数字和 GB2312 的一级汉字。
+func Supported(r rune) bool {
+	_, found := lookup(r)
+	return found
+}
+
+func lookup(r rune) (*glyph, bool) {
+	if g, found := font[r]; found {
+		rows := make([]uint16, GlyphHeight)
+		for i, row := range g {
+			rows[i] = uint16(row)
+		}
+		return &glyph{width: GlyphWidth, height: GlyphHeight, rows: rows}, true
+	}
+	return hanziGlyph(r)
+}
+
+// New 声明 Text 对象
+//
+// runes 需要绘制的字符，最多 MaxGlyphs 个，且都必须是字体中存在的字符；
+// size 图像的尺寸，文字居中绘制，四周至少留出 3/16 的空白；
+func New(runes []rune, size int) *Text {
+	if len(runes) == 0 || len(runes) > MaxGlyphs {
+		panic(fmt.Sprintf("参数 runes 的长度必须介于 [1,%d] 之间", MaxGlyphs))
+	}
+
+	t := &Text{
+		runes:   runes,
+		glyphs:  make([]*glyph, 0, len(runes)),
+		offsets: make([]int, 0, len(runes)),
+	}
+	for _, r := range runes {
+		g, found := lookup(r)
+		if !found {
+			panic(fmt.Sprintf("字体中不存在字符 %q", r))
+		}
+
+		if len(t.glyphs) > 0 { // 字符之间间隔一列
+			t.cols++
+		}
+		t.offsets = append(t.offsets, t.cols)
+		t.glyphs = append(t.glyphs, g)
+		t.cols += g.width
+		if g.height > t.rows {
+			t.rows = g.height
+		}
+	}
+
+	area := size * 5 / 8
+	t.unit = area / t.cols
+	if u := area / t.rows; u < t.unit {
+		t.unit = u
+	}
+	if t.unit < 1 {
+		t.unit = 1
+	}
+	t.left = (size - t.cols*t.unit) / 2
+	t.top = (size - t.rows*t.unit) / 2
+
+	return t
+}
+
+// Runes 绘制的字符
+func (t *Text) Runes() []rune { return t.runes }
+
+// Draw 将文字绘制到 p 上
+func (t *Text) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, t.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+//
+// 文字为 0，其它的部分为 1，即在前景色的背景上以背景色绘制文字。
+// 高度不同的字符在垂直方向上居中对齐。
+func (t *Text) ColorIndexAt(x, y int) uint8 {
+	x -= t.left
+	y -= t.top
+	if x < 0 || y < 0 {
+		return 1
+	}
+
+	col, row := x/t.unit, y/t.unit
+	if col >= t.cols || row >= t.rows {
+		return 1
+	}
+
+	for index, g := range t.glyphs {
+		c := col - t.offsets[index]
+		if c < 0 || c >= g.width {
+			continue
+		}
+
+		r := row - (t.rows-g.height)/2
+		if r < 0 || r >= g.height {
+			return 1
+		}
+		if g.rows[r]>>(g.width-1-c)&1 == 1 {
+			return 0
+		}
+		return 1
+	}
+	return 1
+}
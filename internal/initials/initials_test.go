@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package initials
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+	"unicode"
+
+	"github.com/issue9/assert/v4"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestFont(t *testing.T) {
+	a := assert.New(t, false)
+
+	for r := 'A'; r <= 'Z'; r++ {
+		a.True(Supported(r), "%q", r)
+	}
+	for r := '0'; r <= '9'; r++ {
+		a.True(Supported(r), "%q", r)
+	}
+	a.False(Supported('a')).False(Supported('É')).
+		True(Supported('张')).True(Supported('蔼')).True(Supported('座')).
+		False(Supported('丌')). // 二级汉字
+		False(Supported('😀'))
+
+	for r, g := range font {
+		var empty = true
+		for _, row := range g {
+			a.True(row < 1<<GlyphWidth, "%q", r)
+			empty = empty && row == 0
+		}
+		a.False(empty, "%q", r)
+	}
+
+	// 按码点排列的 3755 个一级汉字
+	a.Equal(len(hanzi)%hanziRecord, 0).Equal(len(hanzi)/hanziRecord, 3755)
+	prev := rune(0)
+	for i := 0; i < len(hanzi); i += hanziRecord {
+		r := rune(binary.BigEndian.Uint16(hanzi[i:]))
+		a.True(r > prev && unicode.Is(unicode.Han, r), "%q", r)
+		prev = r
+
+		g, found := hanziGlyph(r)
+		a.True(found, "%q", r).Equal(g.width, HanziWidth).Length(g.rows, HanziHeight)
+		var empty = true
+		for _, row := range g.rows {
+			a.True(row < 1<<HanziWidth, "%q", r)
+			empty = empty && row == 0
+		}
+		a.False(empty, "%q", r)
+	}
+
+	// 王的第一行和最后一行均为横线
+	g, found := hanziGlyph('王')
+	a.True(found).
+		Equal(g.rows[0], 0b111111111110).
+		Equal(g.rows[1], 0b000001000000).
+		Equal(g.rows[HanziHeight-1], 0b111111111110)
+}
+
+func TestPick(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Nil(Pick("")).
+		Nil(Pick(" .-_ ")).
+		Equal(Pick("caixw"), []rune{'C'}).
+		Equal(Pick("john ronald reuel tolkien"), []rune{'J', 'T'}).
+		Equal(Pick("  ada.lovelace@example.com"), []rune{'A', 'C'}).
+		Equal(Pick("张三"), []rune{'张'}).
+		Equal(Pick("欧阳 修"), []rune{'欧'}).
+		Equal(Pick("tom 张"), []rune{'T'}).
+		Equal(Pick("émile zola"), []rune{'E', 'Z'}).
+		Equal(Pick("Åsa Øberg-Straße"), []rune{'A', 'S'}).
+		Equal(Pick("ßig"), []rune{'S'}).
+		Equal(Pick("Łukasz"), []rune{'L'})
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Panic(func() { New(nil, size) }).
+		Panic(func() { New([]rune{'A', 'B', 'C'}, size) }).
+		Panic(func() { New([]rune{'a'}, size) })
+
+	// 两个字符共 11 列，每列 size*5/8/11 个像素
+	tt := New([]rune{'A', 'B'}, size)
+	a.Equal(tt.unit, 7).
+		Equal(tt.left, (size-11*7)/2).
+		Equal(tt.top, (size-7*7)/2).
+		Equal(tt.Runes(), []rune{'A', 'B'})
+
+	// A 的第一行第一列为空，第二列有值；两个字符之间的空列。
+	a.Equal(tt.ColorIndexAt(tt.left, tt.top), 1).
+		Equal(tt.ColorIndexAt(tt.left+tt.unit, tt.top), 0).
+		Equal(tt.ColorIndexAt(tt.left+5*tt.unit, tt.top+3*tt.unit), 1).
+		Equal(tt.ColorIndexAt(0, 0), 1).
+		Equal(tt.ColorIndexAt(size-1, size-1), 1)
+
+	// 尺寸过小时，每个点至少为 1 个像素。
+	a.Equal(New([]rune{'A', 'B'}, 8).unit, 1)
+
+	// 汉字共 12 列 11 行，每列 size*5/8/12 个像素
+	tt = New([]rune{'王'}, size)
+	a.Equal(tt.unit, 6).
+		Equal(tt.left, (size-12*6)/2).
+		Equal(tt.top, (size-11*6)/2).
+		Equal(tt.ColorIndexAt(tt.left, tt.top), 0).
+		Equal(tt.ColorIndexAt(tt.left+11*tt.unit, tt.top), 1).
+		Equal(tt.ColorIndexAt(tt.left, tt.top+tt.unit), 1).
+		Equal(tt.ColorIndexAt(tt.left+5*tt.unit, tt.top+tt.unit), 0).
+		Equal(tt.ColorIndexAt(tt.left+12*tt.unit, tt.top), 1)
+
+	// 高度不同的字符垂直居中，A 的上下各空出 2 行。
+	tt = New([]rune{'A', '王'}, size)
+	a.Equal(tt.cols, 18).Equal(tt.rows, 11).
+		Equal(tt.ColorIndexAt(tt.left+tt.unit, tt.top), 1).
+		Equal(tt.ColorIndexAt(tt.left+tt.unit, tt.top+2*tt.unit), 0).
+		Equal(tt.ColorIndexAt(tt.left+6*tt.unit, tt.top), 0)
+}
+
+func TestText_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for _, runes := range [][]rune{{'A'}, {'C', 'X'}, {'Q', '7'}, {'W', 'M'}, {'张'}, {'蔼'}} {
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		New(runes, size).Draw(img)
+
+		fi, err := os.Create("./testdata/initials-" + string(runes) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package randomart 实现 OpenSSH 的 randomart
+//
+// 即 ssh-keygen -lv 所采用的 drunken bishop 算法：
+// 从 Width*Height 的区域中间开始，每次读取摘要中的两位，沿对角线移动一步，
+// 最终以每个位置被经过的次数生成图案，结果与 OpenSSH 完全相同。
+package randomart
+
+import (
+	"image"
+	"strings"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+const (
+	Width  = 17 // 区域的列数
+	Height = 9  // 区域的行数
+
+	// 各个位置被经过的次数对应的字符，最后两个字符分别表示起点和终点。
+	symbols = " .o+=*BOX@%&#/^SE"
+
+	start = uint8(len(symbols) - 2)
+	end   = uint8(len(symbols) - 1)
+)
+
+// Art 由摘要生成的 randomart
+type Art struct {
+	field [Height][Width]uint8 // 每个位置对应 symbols 中的下标
+}
+
+// New 根据摘要 digest 生成 Art 对象
+func New(digest []byte) *Art {
+	a := &Art{}
+
+	x, y := Width/2, Height/2
+	for _, b := range digest {
+		for i := 0; i < 4; i++ { // 每个字节从低位开始，每两位表示一次移动。
+			if b&1 == 1 {
+				x++
+			} else {
+				x--
+			}
+			if b&2 == 2 {
+				y++
+			} else {
+				y--
+			}
+			x, y = clamp(x, Width-1), clamp(y, Height-1)
+
+			if a.field[y][x] < start-1 {
+				a.field[y][x]++
+			}
+			b >>= 2
+		}
+	}
+
+	a.field[Height/2][Width/2] = start
+	a.field[y][x] = end
+	return a
+}
+
+func clamp(v, max int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Rows 以行的形式返回图案，不包含边框。
+func (a *Art) Rows() []string {
+	rows := make([]string, 0, Height)
+	for _, line := range a.field {
+		row := make([]byte, 0, Width)
+		for _, v := range line {
+			row = append(row, symbols[v])
+		}
+		rows = append(rows, string(row))
+	}
+	return rows
+}
+
+// String 返回与 ssh-keygen -lv 相同格式的文本
+//
+// title 和 alg 分别显示在上下边框的中间，比如 "ED25519 256" 和 "SHA256"，
+// 不需要包含方括号，为空时不显示。与 OpenSSH 相同，加上方括号之后最多保留 Width-1 个字符，
+// 超出的部分（包括右侧的方括号）会被截断。
+func (a *Art) String(title, alg string) string {
+	var b strings.Builder
+	b.Grow((Width + 3) * (Height + 2))
+
+	border(&b, title)
+	for _, row := range a.Rows() {
+		b.WriteByte('|')
+		b.WriteString(row)
+		b.WriteString("|\n")
+	}
+	border(&b, alg)
+
+	return b.String()
+}
+
+func border(b *strings.Builder, title string) {
+	if title != "" {
+		title = "[" + title + "]"
+		if len(title) > Width-1 { // OpenSSH 中 title 的缓存大小为 FLDSIZE_X，包含结尾的 \0。
+			title = title[:Width-1]
+		}
+	}
+
+	dashes := (Width - len(title)) / 2
+	b.WriteByte('+')
+	b.WriteString(strings.Repeat("-", dashes))
+	b.WriteString(title)
+	b.WriteString(strings.Repeat("-", Width-dashes-len(title)))
+	b.WriteString("+\n")
+}
+
+// Grid 以图片的形式表示 Art
+//
+// 每个位置为一个正方形的格子，被经过的次数越多，格子中间的方块越大，起点和终点填满整个格子。
+type Grid struct {
+	art  *Art
+	unit int // 每个格子的像素
+	left int
+	top  int
+}
+
+// Grid 返回尺寸为 size 的图片
+func (a *Art) Grid(size int) *Grid {
+	unit := size / Width
+	if unit < 1 {
+		unit = 1
+	}
+
+	return &Grid{
+		art:  a,
+		unit: unit,
+		left: (size - Width*unit) / 2,
+		top:  (size - Height*unit) / 2,
+	}
+}
+
+// 格子中方块的边长
+func (g *Grid) side(v uint8) int {
+	if v >= start-1 {
+		return g.unit
+	}
+	return g.unit * (int(v) + 1) / int(start-1)
+}
+
+// Draw 将图案绘制到 p 上
+func (g *Grid) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, g.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (g *Grid) ColorIndexAt(x, y int) uint8 {
+	x -= g.left
+	y -= g.top
+	if x < 0 || y < 0 || x >= Width*g.unit || y >= Height*g.unit {
+		return 0
+	}
+
+	v := g.art.field[y/g.unit][x/g.unit]
+	if v == 0 {
+		return 0
+	}
+
+	side := g.side(v)
+	offset := (g.unit - side) / 2
+	x, y = x%g.unit-offset, y%g.unit-offset
+	if x >= 0 && y >= 0 && x < side && y < side {
+		return 1
+	}
+	return 0
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+func (g *Grid) Path() *vector.Path {
+	p := &vector.Path{}
+	for y, line := range g.art.field {
+		for x, v := range line {
+			if v == 0 {
+				continue
+			}
+
+			side := g.side(v)
+			offset := (g.unit - side) / 2
+			p.Rect(float64(g.left+x*g.unit+offset), float64(g.top+y*g.unit+offset), float64(side), float64(side))
+		}
+	}
+	return p
+}
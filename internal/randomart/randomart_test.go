@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package randomart
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+// 由 ssh-keygen -lv -E sha256|md5 -f key.pub 生成
+var keys = []*struct {
+	title  string
+	key    string // 公钥中 base64 编码的部分
+	sha256 string
+	md5    string
+}{
+	{
+		title: "ED25519 256",
+		key:   "AAAAC3NzaC1lZDI1NTE5AAAAINsmBHTN8/yrgRQHHKolEMhnWVPE6HygusdfKZVlUuOT",
+		sha256: `+--[ED25519 256]--+
+|       .BBB.*o ..|
+|       . Bo@.+.o |
+|      . = = X.B..|
+|       + + + *.+.|
+|      . S + . +..|
+|       o .   + o.|
+|            E + .|
+|             . o |
+|                 |
++----[SHA256]-----+
+`,
+		md5: `+--[ED25519 256]--+
+|     .o.ooo..    |
+|    .. ..+ .     |
+|   o  ..o o      |
+|  . .o.. +       |
+|  E .++ S o      |
+|   . o+o o       |
+|     .. o .      |
+|         .       |
+|                 |
++------[MD5]------+
+`,
+	},
+	{
+		title: "ECDSA 256",
+		key:   "AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBAsvliTuznxGHddACVWp92cJRUvA0gc4NUdkS9d91YPFV9SR0leurVIQguS9WDQSxm+S/N8Ig4+APBRhuootV28=",
+		sha256: `+---[ECDSA 256]---+
+|.==+E..++.       |
+|  =*o. .+        |
+|o *o..o.+.       |
+|.B . +.o o       |
+|. o o ..S .      |
+|   o ..    .     |
+|  . .  o .o      |
+|   o..o ++.o     |
+|  .oo  o+==.     |
++----[SHA256]-----+
+`,
+		md5: `+---[ECDSA 256]---+
+| o++o            |
+|o... . .         |
+|.o.   . .        |
+|o..    .         |
+|.. +    S        |
+|  . + E  o       |
+| . . o    + o    |
+|  o        = .   |
+|   .      ..o.   |
++------[MD5]------+
+`,
+	},
+	{
+		title: "RSA 1024",
+		key:   "AAAAB3NzaC1yc2EAAAADAQABAAAAgQDMVvkroF0TTTkQzKcwC3sm/SmmHvscDQ5szPHKJRK1/l0zKHVNZmZhYbtu8y5LOUYiWvsn11gq7PGRgKHz4wRkhOBBLpHlVDfVeNGUPXYsCQAVtiJMjeHNwZ7gayyT2SUQyqoh7MAGnL5l1tZxBLbNKMuE8WlCiOXfbKh4pX4IBw==",
+		sha256: `+---[RSA 1024]----+
+|                 |
+|   .             |
+|  o.o . .  o = . |
+|  .o.o =o++.O =  |
+| .... +oS++B +   |
+|...o. .*ooo o    |
+|.o. ..o.o..      |
+|  .  o +=.       |
+|      oEo.       |
++----[SHA256]-----+
+`,
+		md5: `+---[RSA 1024]----+
+|              o  |
+|     . o     . o |
+|    . B o   . .  |
+|   . + B . . .   |
+|  . o + S . o    |
+| o . .   + o     |
+|  o .   . o      |
+|   .   .  . E    |
+|        .. .     |
++------[MD5]------+
+`,
+	},
+}
+
+func TestArt_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, k := range keys {
+		blob, err := base64.StdEncoding.DecodeString(k.key)
+		a.NotError(err)
+
+		s := sha256.Sum256(blob)
+		a.Equal(New(s[:]).String(k.title, "SHA256"), k.sha256)
+
+		m := md5.Sum(blob)
+		a.Equal(New(m[:]).String(k.title, "MD5"), k.md5)
+	}
+
+	// 没有标题以及过长的标题，没有移动时终点与起点相同。
+	// 与 ssh-keygen 相同，过长的标题保留 16 个字符。
+	s := New(nil).String("", "0123456789abcdefghij")
+	a.Equal(s[:Width+3], "+-----------------+\n").
+		Equal(s[len(s)-Width-3:], "+[0123456789abcde-+\n").
+		Contains(s, "|        E        |\n")
+
+	// 加上方括号之后正好 16 个字符时不截断
+	s = New(nil).String("0123456789abcd", "")
+	a.Equal(s[:Width+3], "+[0123456789abcd]-+\n")
+}
+
+func TestArt_Rows(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 每次都向左上移动，到达上边之后沿着上边继续向左，最终停留在左上角。
+	rows := New(make([]byte, 10)).Rows()
+	a.Length(rows, Height).
+		Equal(rows[0], "E....            ").
+		Equal(rows[1], "     .           ").
+		Equal(rows[4], "        S        ")
+
+	// 经过的次数不超过 ^ 对应的值
+	// 在 (7,5) 和起点之间来回移动，最终回到起点。
+	rows = New(bytes.Repeat([]byte{0b01_10_01_10}, 8)).Rows()
+	a.Equal(rows[4], "        E        ").
+		Equal(rows[5], "       ^         ")
+}
+
+func TestGrid(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i, k := range keys {
+		blob, err := base64.StdEncoding.DecodeString(k.key)
+		a.NotError(err)
+		s := sha256.Sum256(blob)
+		g := New(s[:]).Grid(size)
+		a.Equal(g.unit, 7).Equal(g.left, 4).Equal(g.top, 32)
+
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		g.Draw(img)
+		a.Equal(img.ColorIndexAt(0, 0), 0)
+
+		// 起点填满整个格子
+		cx, cy := g.left+Width/2*g.unit, g.top+Height/2*g.unit
+		a.Equal(img.ColorIndexAt(cx, cy), 1).
+			Equal(img.ColorIndexAt(cx+g.unit-1, cy+g.unit-1), 1)
+
+		cmds := g.Path().Commands()
+		a.True(len(cmds) > 0)
+		for _, cmd := range cmds {
+			for _, pt := range cmd.Points {
+				a.True(pt.X >= 0 && pt.X <= float64(size) && pt.Y >= 0 && pt.Y <= float64(size))
+			}
+		}
+
+		fi, err := os.Create("./testdata/randomart-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+
+	a.Equal(New(nil).Grid(8).unit, 1)
+}
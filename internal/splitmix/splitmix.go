@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package splitmix 基于 SplitMix64 算法的伪随机数生成器
+//
+// 算法固定，不受标准库实现变化的影响，相同的种子始终生成相同的序列，
+// 用于由 hash 值扩展出更多的随机数。
+package splitmix
+
+// Rand 伪随机数生成器
+//
+// 其值即为当前的状态，可以直接由种子转换而来，比如 splitmix.Rand(seed)。
+type Rand uint64
+
+// Uint64 返回下一个随机数
+func (r *Rand) Uint64() uint64 {
+	*r += 0x9e3779b97f4a7c15
+	z := uint64(*r)
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// Intn 返回 [0,n) 之间的随机数
+func (r *Rand) Intn(n int) int { return int(r.Uint64() % uint64(n)) }
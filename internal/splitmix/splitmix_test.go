@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package splitmix
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestRand(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 以 0 为种子时的前两个值
+	r := Rand(0)
+	a.Equal(r.Uint64(), uint64(0xe220a8397b1dcdaf)).
+		Equal(r.Uint64(), uint64(0x6e789e6aa1b965f4))
+
+	// 相同的种子生成相同的序列
+	r1, r2 := Rand(12345), Rand(12345)
+	for k := 0; k < 10; k++ {
+		a.Equal(r1.Uint64(), r2.Uint64())
+	}
+
+	r = Rand(1)
+	for k := 0; k < 100; k++ {
+		n := r.Intn(7)
+		a.True(n >= 0 && n < 7)
+	}
+}
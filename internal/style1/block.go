@@ -4,11 +4,15 @@
 
 package style1
 
-import "image"
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
 
 var (
-	// 可以出现在中间的方块，一般为了美观，都是对称图像。
-	centerBlocks = []blockFunc{b0, b1, b2, b3, b19, b26, b27}
+	// 可以出现在中间的方块在 blocks 中的下标，一般为了美观，都是对称图像。
+	centerBlocks = []int{0, 1, 2, 3, 19, 26, 27}
 
 	// 所有方块
 	blocks = []blockFunc{
@@ -19,26 +23,75 @@ var (
 )
 
 // 所有 block 函数的类型
-type blockFunc func(img *image.Paletted, x, y, size, angle int)
+type blockFunc func(img *canvas, x, y, size, angle int)
+
+// 方块的绘制目标
+type canvas struct {
+	*image.Paletted
+
+	// 是否保留同一方块中之前绘制的多边形
+	//
+	// V1 版本中，每个多边形都会将其所在方块的其它区域重置为背景色，
+	// 所以由多个多边形组成的方块，最终只会显示最后一个多边形。
+	union bool
+
+	// 不为空时，同时以矢量的形式记录绘制的内容。
+	path  *vector.Path
+	start int // 当前方块在 path 中的起始位置
+}
 
 // 将多边形 points 旋转 angle 个角度，然后输出到 img 上，起点为 x,y 坐标
 //
 // points 中的坐标是基于左上角是原点的坐标系。
-func drawBlock(img *image.Paletted, x, y, size, angle int, points []int) {
+func drawBlock(img *canvas, x, y, size, angle int, points []int) {
 	if angle > 0 { // 0 角度不需要转换
 		m := size / 2
 		rotate(points, m, m, angle)
 	}
 
-	for i := 0; i < size; i++ {
-		for j := 0; j < size; j++ {
-			var index uint8
+	r := clip(img, x, y, size, size)
+	for i := r.Min.X - x; i < r.Max.X-x; i++ {
+		for j := r.Min.Y - y; j < r.Max.Y-y; j++ {
 			if pointInPolygon(i, j, points) {
-				index = 1
+				img.SetColorIndex(x+i, y+j, 1)
+			} else if !img.union {
+				img.SetColorIndex(x+i, y+j, 0)
 			}
-			img.SetColorIndex(x+i, y+j, index)
 		}
 	}
+
+	if img.path != nil {
+		if !img.union {
+			img.path.Truncate(img.start)
+		}
+
+		fp := make([]float64, 0, len(points))
+		for i := 0; i < len(points); i += 2 {
+			fp = append(fp, float64(x+points[i]), float64(y+points[i+1]))
+		}
+		img.path.Polygon(fp...)
+	}
+}
+
+// 以前景色填充 x,y,width,height 指定的矩形
+func fillRect(img *canvas, x, y, width, height int) {
+	r := clip(img, x, y, width, height)
+	for i := r.Min.X; i < r.Max.X; i++ {
+		for j := r.Min.Y; j < r.Max.Y; j++ {
+			img.SetColorIndex(i, j, 1)
+		}
+	}
+
+	if img.path != nil {
+		img.path.Rect(float64(x), float64(y), float64(width), float64(height))
+	}
+}
+
+// 返回 x,y,width,height 与 img 相交的部分
+//
+// 只绘制与 img 相交的部分，当 img 仅包含单个像素时，可以快速计算该像素的值。
+func clip(img *canvas, x, y, width, height int) image.Rectangle {
+	return image.Rect(x, y, x+width, y+height).Intersect(img.Rect)
 }
 
 // 全空白
@@ -48,7 +101,7 @@ func drawBlock(img *image.Paletted, x, y, size, angle int, points []int) {
 //	|      |
 //	|      |
 //	--------
-func b0(img *image.Paletted, x, y, size, angle int) {}
+func b0(img *canvas, x, y, size, angle int) {}
 
 // 全填充正方形
 //
@@ -57,12 +110,8 @@ func b0(img *image.Paletted, x, y, size, angle int) {}
 //	|######|
 //	|######|
 //	--------
-func b1(img *image.Paletted, x, y, size, angle int) {
-	for i := x; i < x+size; i++ {
-		for j := y; j < y+size; j++ {
-			img.SetColorIndex(i, j, 1)
-		}
-	}
+func b1(img *canvas, x, y, size, angle int) {
+	fillRect(img, x, y, size, size)
 }
 
 // 中间小方块
@@ -73,16 +122,9 @@ func b1(img *image.Paletted, x, y, size, angle int) {
 //	|  ####  |
 //	|        |
 //	----------
-func b2(img *image.Paletted, x, y, size, angle int) {
+func b2(img *canvas, x, y, size, angle int) {
 	l := size / 4
-	x = x + l
-	y = y + l
-
-	for i := x; i < x+2*l; i++ {
-		for j := y; j < y+2*l; j++ {
-			img.SetColorIndex(i, j, 1)
-		}
-	}
+	fillRect(img, x+l, y+l, 2*l, 2*l)
 }
 
 // 菱形
@@ -96,7 +138,7 @@ func b2(img *image.Paletted, x, y, size, angle int) {
 //	|  ###  |
 //	|   #   |
 //	---------
-func b3(img *image.Paletted, x, y, size, angle int) {
+func b3(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, 0, []int{
 		m, 0,
@@ -114,7 +156,7 @@ func b3(img *image.Paletted, x, y, size, angle int) {
 // |##   |
 // |#    |
 // |------
-func b4(img *image.Paletted, x, y, size, angle int) {
+func b4(img *canvas, x, y, size, angle int) {
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
 		size, 0,
@@ -128,7 +170,7 @@ func b4(img *image.Paletted, x, y, size, angle int) {
 // |  ###  |
 // | ##### |
 // |#######|
-func b5(img *image.Paletted, x, y, size, angle int) {
+func b5(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		m, 0,
@@ -145,7 +187,7 @@ func b5(img *image.Paletted, x, y, size, angle int) {
 //	|###   |
 //	|###   |
 //	--------
-func b6(img *image.Paletted, x, y, size int, angle int) {
+func b6(img *canvas, x, y, size int, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
@@ -164,7 +206,7 @@ func b6(img *image.Paletted, x, y, size int, angle int) {
 //	|  #####|
 //	|   ####|
 //	|--------
-func b7(img *image.Paletted, x, y, size, angle int) {
+func b7(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
@@ -185,7 +227,7 @@ func b7(img *image.Paletted, x, y, size, angle int) {
 //	| ### ### |
 //	|#########|
 //	-----------
-func b8(img *image.Paletted, x, y, size, angle int) {
+func b8(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	mm := m / 2
 
@@ -223,7 +265,7 @@ func b8(img *image.Paletted, x, y, size, angle int) {
 //	|  #### |
 //	|   #   |
 //	---------
-func b9(img *image.Paletted, x, y, size int, angle int) {
+func b9(img *canvas, x, y, size int, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
@@ -243,7 +285,7 @@ func b9(img *image.Paletted, x, y, size int, angle int) {
 // |##      |
 // |#       |
 // ----------
-func b10(img *image.Paletted, x, y, size, angle int) {
+func b10(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		m, 0,
@@ -269,7 +311,7 @@ func b10(img *image.Paletted, x, y, size, angle int) {
 //	|        |
 //	|        |
 //	----------
-func b11(img *image.Paletted, x, y, size, angle int) {
+func b11(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
@@ -287,7 +329,7 @@ func b11(img *image.Paletted, x, y, size, angle int) {
 // |  #####  |
 // |    #    |
 // -----------
-func b12(img *image.Paletted, x, y, size, angle int) {
+func b12(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, m,
@@ -304,7 +346,7 @@ func b12(img *image.Paletted, x, y, size, angle int) {
 // |  #####  |
 // |#########|
 // -----------
-func b13(img *image.Paletted, x, y, size, angle int) {
+func b13(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		m, m,
@@ -321,7 +363,7 @@ func b13(img *image.Paletted, x, y, size, angle int) {
 // |       |
 // |       |
 // ---------
-func b14(img *image.Paletted, x, y, size, angle int) {
+func b14(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		m, 0,
@@ -338,7 +380,7 @@ func b14(img *image.Paletted, x, y, size, angle int) {
 // |        |
 // |        |
 // ----------
-func b15(img *image.Paletted, x, y, size, angle int) {
+func b15(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		0, 0,
@@ -356,7 +398,7 @@ func b15(img *image.Paletted, x, y, size, angle int) {
 // | ##### |
 // |#######|
 // ---------
-func b16(img *image.Paletted, x, y, size, angle int) {
+func b16(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	drawBlock(img, x, y, size, angle, []int{
 		m, 0,
@@ -380,7 +422,7 @@ func b16(img *image.Paletted, x, y, size, angle int) {
 // |      ##|
 // |      ##|
 // ----------
-func b17(img *image.Paletted, x, y, size, angle int) {
+func b17(img *canvas, x, y, size, angle int) {
 	m := size / 2
 
 	drawBlock(img, x, y, size, angle, []int{
@@ -407,7 +449,7 @@ func b17(img *image.Paletted, x, y, size, angle int) {
 // |##      |
 // |#       |
 // ----------
-func b18(img *image.Paletted, x, y, size, angle int) {
+func b18(img *canvas, x, y, size, angle int) {
 	m := size / 2
 
 	drawBlock(img, x, y, size, angle, []int{
@@ -425,7 +467,7 @@ func b18(img *image.Paletted, x, y, size, angle int) {
 // |###  ###|
 // |########|
 // ----------
-func b19(img *image.Paletted, x, y, size, angle int) {
+func b19(img *canvas, x, y, size, angle int) {
 	m := size / 2
 
 	drawBlock(img, x, y, size, angle, []int{
@@ -464,7 +506,7 @@ func b19(img *image.Paletted, x, y, size, angle int) {
 // |##       |
 // |#        |
 // ----------
-func b20(img *image.Paletted, x, y, size, angle int) {
+func b20(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -483,7 +525,7 @@ func b20(img *image.Paletted, x, y, size, angle int) {
 // |##      |
 // |#       |
 // ----------
-func b21(img *image.Paletted, x, y, size, angle int) {
+func b21(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -509,7 +551,7 @@ func b21(img *image.Paletted, x, y, size, angle int) {
 // |##    ##|
 // |#      #|
 // ----------
-func b22(img *image.Paletted, x, y, size, angle int) {
+func b22(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -535,7 +577,7 @@ func b22(img *image.Paletted, x, y, size, angle int) {
 // |##      |
 // |#       |
 // ----------
-func b23(img *image.Paletted, x, y, size, angle int) {
+func b23(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -561,7 +603,7 @@ func b23(img *image.Paletted, x, y, size, angle int) {
 // |##  ##  |
 // |#   #   |
 // ----------
-func b24(img *image.Paletted, x, y, size, angle int) {
+func b24(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -587,7 +629,7 @@ func b24(img *image.Paletted, x, y, size, angle int) {
 // |######  |
 // |####    |
 // ----------
-func b25(img *image.Paletted, x, y, size, angle int) {
+func b25(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -613,7 +655,7 @@ func b25(img *image.Paletted, x, y, size, angle int) {
 // |###  ###|
 // |#      #|
 // ----------
-func b26(img *image.Paletted, x, y, size, angle int) {
+func b26(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
@@ -653,7 +695,7 @@ func b26(img *image.Paletted, x, y, size, angle int) {
 // |###   ##|
 // |########|
 // ----------
-func b27(img *image.Paletted, x, y, size, angle int) {
+func b27(img *canvas, x, y, size, angle int) {
 	m := size / 2
 	q := size / 4
 
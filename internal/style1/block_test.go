@@ -27,10 +27,12 @@ func TestBlocks(t *testing.T) {
 	p := []color.Color{back, fore}
 
 	for k, v := range blocks {
-		img := image.NewPaletted(image.Rect(0, 0, size*4, size), p) // 横向 4 张图片大小
+		img := image.NewPaletted(image.Rect(0, 0, size*4, size*2), p) // 横向 4 张图片大小
 
+		// 第一行为 V1，第二行为 V2
 		for i := 0; i < 4; i++ {
-			v(img, i*size, 0, size, i)
+			v(&canvas{Paletted: img}, i*size, 0, size, i)
+			v(&canvas{Paletted: img, union: true}, i*size, size, size, i)
 		}
 
 		fi, err := os.Create("./testdata/block-" + strconv.Itoa(k) + ".png")
@@ -44,13 +46,20 @@ func TestBlocks(t *testing.T) {
 func TestDrawBlocks(t *testing.T) {
 	a := assert.New(t, false)
 
-	for i := 0; i < 20; i++ {
-		p := image.NewPaletted(image.Rect(0, 0, size, size), []color.Color{back, fore})
-		DrawBlocks(p, size, uint32(11132323+i))
+	for _, v := range []Version{V1, V2} {
+		for i := 0; i < 20; i++ {
+			p := image.NewPaletted(image.Rect(0, 0, size, size), []color.Color{back, fore})
+			DrawBlocks(p, size, uint32(11132323+i), v, Rotate)
 
-		fi, err := os.Create("./testdata/draw-" + strconv.Itoa(i) + ".png")
-		a.NotError(err).NotNil(fi)
-		a.NotError(png.Encode(fi, p))
-		a.NotError(fi.Close()) // 关闭文件
+			fi, err := os.Create("./testdata/draw-v" + strconv.Itoa(int(v)) + "-" + strconv.Itoa(i) + ".png")
+			a.NotError(err).NotNil(fi)
+			a.NotError(png.Encode(fi, p))
+			a.NotError(fi.Close()) // 关闭文件
+		}
 	}
+
+	a.Panic(func() {
+		p := image.NewPaletted(image.Rect(0, 0, size, size), []color.Color{back, fore})
+		DrawBlocks(p, size, 1, 0, Rotate)
+	})
 }
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+// Choice 图像中各个方块的选取结果
+//
+// 方块以其在所有方块中的下标表示，角度取值为 0-3，分别表示 0、90、180 和 270 度。
+// Corner 和 Edge 分别为左上角和顶部中间的方块，对称方式为 Rotate 时，
+// 其它角落和边上的方块由这两者依次顺时针旋转 90 度得到，其它对称方式需要参考 Cells。
+type Choice struct {
+	Corner, CornerAngle int
+	Edge, EdgeAngle     int
+	Center              int
+
+	// 四个角和四条边上的方块，从左上角开始顺时针排列。
+	//
+	// 对称方式为镜像时，这里是镜像之前的内容，镜像的区域会被左侧、上方或是对角线左下方的内容覆盖。
+	Cells [8]Cell
+}
+
+// Cell 单个位置上的方块
+type Cell struct {
+	Block, Angle int
+}
+
+// Choose 返回 DrawBlocks 所选取的方块
+func Choose(size int, sum uint32, v Version, s Symmetry) Choice {
+	l := layout(size, sum, v, s)
+	c := Choice{
+		Corner:      l[1].block,
+		CornerAngle: l[1].angle,
+		Edge:        l[2].block,
+		EdgeAngle:   l[2].angle,
+		Center:      l[0].block,
+	}
+	for k := range c.Cells {
+		c.Cells[k] = Cell{Block: l[k+1].block, Angle: l[k+1].angle}
+	}
+	return c
+}
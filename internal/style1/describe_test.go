@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestChoose(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := Choose(size, 0x1f_02_1d_1e, V2, Rotate)
+	a.Equal(c, Choice{
+		Corner: 2, CornerAngle: 3, Edge: 1, EdgeAngle: 3, Center: 2,
+		Cells: [8]Cell{{2, 3}, {1, 3}, {2, 0}, {1, 0}, {2, 1}, {1, 1}, {2, 2}, {1, 2}},
+	})
+
+	// V1 的角度始终为 0
+	c = Choose(size, 0x1f_02_1d_1e, V1, Rotate)
+	a.Equal(c.CornerAngle, 0).Equal(c.EdgeAngle, 0)
+
+	for i := 0; i < 100; i++ {
+		c := Choose(size, uint32(11132323+i*7919), V2, Rotate)
+		a.True(c.Corner >= 0 && c.Corner < len(blocks)).
+			True(c.Edge >= 0 && c.Edge < len(blocks)).
+			True(c.CornerAngle >= 0 && c.CornerAngle < 4).
+			True(c.EdgeAngle >= 0 && c.EdgeAngle < 4)
+
+		var found bool
+		for _, index := range centerBlocks {
+			if index == c.Center {
+				found = true
+				break
+			}
+		}
+		a.True(found)
+	}
+}
+
+func TestChoose_none(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 各个位置的方块互不相关，不再是四个角相同且四条边相同。
+	var corners, edges int
+	for i := 0; i < 100; i++ {
+		sum := uint32(11132323 + i*7919)
+		c := Choose(size, sum, V2, None)
+		a.Equal(c.Corner, c.Cells[0].Block).
+			Equal(c.Edge, c.Cells[1].Block).
+			Equal(c.Center, Choose(size, sum, V2, Rotate).Center)
+
+		blocks := map[int]bool{}
+		for k := 0; k < 8; k += 2 {
+			blocks[c.Cells[k].Block] = true
+		}
+		if len(blocks) > 1 {
+			corners++
+		}
+
+		blocks = map[int]bool{}
+		for k := 1; k < 8; k += 2 {
+			blocks[c.Cells[k].Block] = true
+		}
+		if len(blocks) > 1 {
+			edges++
+		}
+	}
+	a.True(corners > 90).True(edges > 90)
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import "sync"
+
+// Lazy 按需计算各个像素的值
+//
+// 每个方格在第一次被访问时整体渲染一次，之后的像素直接从渲染结果中读取，
+// 如果是通过 Masks.Lazy 创建的，则直接采用预先渲染的方块。
+type Lazy struct {
+	size      int
+	blockSize int
+	symmetry  Symmetry
+	layout    [9]placement
+	cells     [9]*lazyCell // 与 layout 一一对应，方块和角度相同的方格共用同一个对象。
+}
+
+type lazyCell struct {
+	once   sync.Once
+	pix    []uint8
+	render func() []uint8
+}
+
+func (c *lazyCell) get() []uint8 {
+	c.once.Do(func() { c.pix = c.render() })
+	return c.pix
+}
+
+// NewLazy 声明 Lazy 对象
+//
+// 参数与 DrawBlocks 的参数相同。
+func NewLazy(size int, sum uint32, v Version, s Symmetry) *Lazy {
+	union := v != V1
+	blockSize := size / 3
+	return newLazy(size, layout(size, sum, v, s), s, func(block, angle int) func() []uint8 {
+		return func() []uint8 { return renderBlock(block, angle, blockSize, union) }
+	})
+}
+
+// Lazy 声明采用预先渲染的方块的 Lazy 对象
+//
+// 参数与 Masks.DrawBlocks 的参数相同。
+func (m *Masks) Lazy(sum uint32, s Symmetry) *Lazy {
+	return newLazy(m.size, layout(m.size, sum, m.version, s), s, func(block, angle int) func() []uint8 {
+		return func() []uint8 { return m.blocks[block][angle] }
+	})
+}
+
+func newLazy(size int, layout [9]placement, s Symmetry, render func(block, angle int) func() []uint8) *Lazy {
+	l := &Lazy{
+		size:      size,
+		blockSize: size / 3,
+		symmetry:  s,
+		layout:    layout,
+	}
+
+	cells := make(map[[2]int]*lazyCell, len(layout))
+	for index, pl := range layout {
+		key := [2]int{pl.block, pl.angle}
+		c, found := cells[key]
+		if !found {
+			c = &lazyCell{render: render(pl.block, pl.angle)}
+			cells[key] = c
+		}
+		l.cells[index] = c
+	}
+
+	return l
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+//
+// 其结果与 DrawBlocks 绘制在同一位置的结果相同。
+func (l *Lazy) ColorIndexAt(x, y int) uint8 {
+	x, y = l.symmetry.source(area(l.size), x, y)
+	if x < 0 || y < 0 || x >= l.size || y >= l.size { // 与 DrawBlocks 相同，超出图像的部分不会被绘制。
+		return 0
+	}
+
+	for index, pl := range l.layout {
+		dx, dy := x-pl.x, y-pl.y
+		if dx < 0 || dy < 0 || dx >= l.blockSize || dy >= l.blockSize {
+			continue
+		}
+		return l.cells[index].get()[dy*l.blockSize+dx]
+	}
+
+	return 0
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestLazy_ColorIndexAt(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for _, s := range []int{MinSize, 100, size} {
+		for _, v := range []Version{V1, V2} {
+			for i := 0; i < 10; i++ {
+				sum := uint32(11132323 + i*7919)
+				sym := Symmetry(i) % symmetryEnd
+
+				img := image.NewPaletted(image.Rect(0, 0, s, s), p)
+				DrawBlocks(img, s, sum, v, sym)
+
+				l := NewLazy(s, sum, v, sym)
+				ml := NewMasks(s, v).Lazy(sum, sym)
+				for y := 0; y < s; y++ {
+					for x := 0; x < s; x++ {
+						a.Equal(l.ColorIndexAt(x, y), img.ColorIndexAt(x, y), "v=%d,size=%d,sum=%d,symmetry=%d,x=%d,y=%d", v, s, sum, sym, x, y).
+							Equal(ml.ColorIndexAt(x, y), img.ColorIndexAt(x, y), "v=%d,size=%d,sum=%d,symmetry=%d,x=%d,y=%d", v, s, sum, sym, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNewLazy_cells(t *testing.T) {
+	a := assert.New(t, false)
+
+	l := NewLazy(size, 11132323, V2, Rotate)
+	for i, pl := range l.layout {
+		a.NotNil(l.cells[i]).Nil(l.cells[i].pix)
+
+		// 方块和角度相同的方格共用同一个对象
+		for j := 0; j < i; j++ {
+			same := l.layout[j].block == pl.block && l.layout[j].angle == pl.angle
+			a.Equal(l.cells[j] == l.cells[i], same, "i=%d,j=%d", i, j)
+		}
+	}
+
+	// 只在访问时渲染所在的方格
+	pl := l.layout[4]
+	l.ColorIndexAt(pl.x+1, pl.y+1)
+	var rendered int
+	for _, c := range l.cells {
+		if c.pix != nil {
+			rendered++
+		}
+	}
+	a.Equal(rendered, 1).NotNil(l.cells[4].pix)
+}
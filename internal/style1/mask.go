@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+	"image/color"
+)
+
+var maskPalette = []color.Color{color.Transparent, color.Black}
+
+// Masks 预先渲染的方块
+//
+// 对于固定尺寸的图像，所有方块在各个角度下的图像都是固定的，
+// 预先渲染之后，绘制图像时只需要复制对应的数据即可。
+type Masks struct {
+	size      int
+	blockSize int
+	version   Version
+	blocks    [][4][]uint8 // 与 blocks 一一对应，每个方块 4 个角度。
+}
+
+// NewMasks 预先渲染尺寸为 size 的图像中的所有方块
+//
+// v 算法的版本；
+func NewMasks(size int, v Version) *Masks {
+	blockSize := size / 3
+	m := &Masks{
+		size:      size,
+		blockSize: blockSize,
+		version:   v,
+		blocks:    make([][4][]uint8, len(blocks)),
+	}
+	for index := range blocks {
+		for angle := 0; angle < 4; angle++ {
+			m.blocks[index][angle] = renderBlock(index, angle, blockSize, v != V1)
+		}
+	}
+
+	return m
+}
+
+// 渲染单个方块，返回值的每个元素表示一个像素，1 为前景色。
+func renderBlock(block, angle, blockSize int, union bool) []uint8 {
+	img := image.NewPaletted(image.Rect(0, 0, blockSize, blockSize), maskPalette)
+	blocks[block](&canvas{Paletted: img, union: union}, 0, 0, blockSize, angle)
+	return img.Pix
+}
+
+// DrawBlocks 将九个方格都填上内容
+//
+// 功能与 DrawBlocks 相同，但是采用预先渲染的方块。
+func (m *Masks) DrawBlocks(p *image.Paletted, sum uint32, s Symmetry) {
+	for _, pl := range layout(m.size, sum, m.version, s) {
+		pix := m.blocks[pl.block][pl.angle]
+
+		// 当 padding 不能整除时，方块可能会超出图像的范围，需要裁剪。
+		r := image.Rect(pl.x, pl.y, pl.x+m.blockSize, pl.y+m.blockSize).Intersect(p.Rect)
+		w := r.Dx()
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			start := p.PixOffset(r.Min.X, y)
+			offset := (y-pl.y)*m.blockSize + r.Min.X - pl.x
+			copy(p.Pix[start:start+w], pix[offset:offset+w])
+		}
+	}
+	s.apply(p, m.size)
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestMasks_DrawBlocks(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for _, s := range []int{MinSize, 100, size} {
+		for _, v := range []Version{V1, V2} {
+			m := NewMasks(s, v)
+			a.NotNil(m)
+
+			for i := 0; i < 50; i++ {
+				sum := uint32(11132323 + i*7919)
+				sym := Symmetry(i) % symmetryEnd
+
+				img1 := image.NewPaletted(image.Rect(0, 0, s, s), p)
+				DrawBlocks(img1, s, sum, v, sym)
+
+				img2 := image.NewPaletted(image.Rect(0, 0, s, s), p)
+				m.DrawBlocks(img2, sum, sym)
+
+				a.Equal(img1.Pix, img2.Pix, "v=%d,size=%d,sum=%d,symmetry=%d", v, s, sum, sym)
+			}
+		}
+	}
+}
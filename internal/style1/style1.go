@@ -5,23 +5,64 @@
 // Package style1 风格 1 的头像
 package style1
 
-import "image"
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/splitmix"
+)
 
 const MinSize = 24
 
+// Version 算法的版本
+//
+// 同一版本的算法对于相同的输入，其输出始终保持一致。
+type Version int8
+
+const (
+	V1 Version = iota + 1 // 初始版本
+	V2                    // 修正了 V1 中方块和角度选取不均匀以及多边形相互覆盖的问题
+)
+
+// 单个方块在图像中的位置及其内容
+type placement struct {
+	block int // 在 blocks 中的下标
+	x, y  int
+	angle int
+}
+
 // DrawBlocks 将九个方格都填上内容
 //
 // sum 由 hash 计算出的随机数；
-func DrawBlocks(p *image.Paletted, size int, sum uint32) {
-	b1 := int(sum&0x00_00_00_ff) % len(blocks)
-	b2 := int(sum&0x00_00_ff_00) % len(blocks)
-	c := int(sum&0x00_ff_00_00) % len(centerBlocks)
-	b1Angle := int(sum&0x0f_00_00_00) % 4
-	b2Angle := int(sum&0xf0_00_00_00) % 4
+// v 算法的版本；
+// s 图像的对称方式；
+func DrawBlocks(p *image.Paletted, size int, sum uint32, v Version, s Symmetry) {
+	blockSize := size / 3
+	c := &canvas{Paletted: p, union: v != V1}
+	for _, pl := range layout(size, sum, v, s) {
+		blocks[pl.block](c, pl.x, pl.y, blockSize, pl.angle)
+	}
+	s.apply(p, size)
+}
 
-	cc := centerBlocks[c]
-	bb1 := blocks[b1]
-	bb2 := blocks[b2]
+// 根据 sum 计算出九个方格各自的内容
+func layout(size int, sum uint32, v Version, s Symmetry) [9]placement {
+	var b1, b2, c, b1Angle, b2Angle int
+	switch v {
+	case V1:
+		b1 = int(sum&0x00_00_00_ff) % len(blocks)
+		b2 = int(sum&0x00_00_ff_00) % len(blocks)
+		c = int(sum&0x00_ff_00_00) % len(centerBlocks)
+		b1Angle = int(sum&0x0f_00_00_00) % 4
+		b2Angle = int(sum&0xf0_00_00_00) % 4
+	case V2:
+		b1 = int(sum&0xff) % len(blocks)
+		b2 = int(sum>>8&0xff) % len(blocks)
+		c = int(sum>>16&0xff) % len(centerBlocks)
+		b1Angle = int(sum>>24) & 0b11
+		b2Angle = int(sum>>26) & 0b11
+	default:
+		panic("无效的 Version")
+	}
 
 	incr := func(a int) int {
 		if a >= 3 {
@@ -36,23 +77,35 @@ func DrawBlocks(p *image.Paletted, size int, sum uint32) {
 	blockSize := size / 3
 	twoBlockSize := 2 * blockSize
 
-	cc(p, blockSize+padding, blockSize+padding, blockSize, 0)
+	var ret [9]placement
+	ret[0] = placement{block: centerBlocks[c], x: blockSize + padding, y: blockSize + padding}
 
-	bb1(p, 0+padding, 0+padding, blockSize, b1Angle)
-	bb2(p, blockSize+padding, 0+padding, blockSize, b2Angle)
+	ret[1] = placement{block: b1, x: 0 + padding, y: 0 + padding, angle: b1Angle}
+	ret[2] = placement{block: b2, x: blockSize + padding, y: 0 + padding, angle: b2Angle}
 
 	b1Angle = incr(b1Angle)
 	b2Angle = incr(b2Angle)
-	bb1(p, twoBlockSize+padding, 0+padding, blockSize, b1Angle)
-	bb2(p, twoBlockSize+padding, blockSize+padding, blockSize, b2Angle)
+	ret[3] = placement{block: b1, x: twoBlockSize + padding, y: 0 + padding, angle: b1Angle}
+	ret[4] = placement{block: b2, x: twoBlockSize + padding, y: blockSize + padding, angle: b2Angle}
 
 	b1Angle = incr(b1Angle)
 	b2Angle = incr(b2Angle)
-	bb1(p, twoBlockSize+padding, twoBlockSize+padding, blockSize, b1Angle)
-	bb2(p, blockSize+padding, twoBlockSize+padding, blockSize, b2Angle)
+	ret[5] = placement{block: b1, x: twoBlockSize + padding, y: twoBlockSize + padding, angle: b1Angle}
+	ret[6] = placement{block: b2, x: blockSize + padding, y: twoBlockSize + padding, angle: b2Angle}
 
 	b1Angle = incr(b1Angle)
 	b2Angle = incr(b2Angle)
-	bb1(p, 0+padding, twoBlockSize+padding, blockSize, b1Angle)
-	bb2(p, 0+padding, blockSize+padding, blockSize, b2Angle)
+	ret[7] = placement{block: b1, x: 0 + padding, y: twoBlockSize + padding, angle: b1Angle}
+	ret[8] = placement{block: b2, x: 0 + padding, y: blockSize + padding, angle: b2Angle}
+
+	if s == None { // 各个位置的方块和角度均由 sum 扩展出的随机数单独决定
+		rand := splitmix.Rand(sum)
+		for k := 1; k < len(ret); k++ {
+			r := rand.Uint64()
+			ret[k].block = int(r&0xff) % len(blocks)
+			ret[k].angle = int(r>>8) & 0b11
+		}
+	}
+
+	return ret
 }
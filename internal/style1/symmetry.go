@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import "image"
+
+// Symmetry 图像的对称方式
+type Symmetry int8
+
+const (
+	Rotate   Symmetry = iota // 四个角和四条边上的方块依次旋转 90 度，默认值。
+	MirrorX                  // 左右镜像对称
+	MirrorXY                 // 上下和左右均镜像对称
+	Diagonal                 // 以左上至右下的对角线镜像对称
+	None                     // 不对称，四个角和四条边上各个位置的方块及其角度互不相关。
+	symmetryEnd
+)
+
+var symmetryNames = [...]string{"rotate", "mirror-x", "mirror-xy", "diagonal", "none"}
+
+// IsValid 是否为有效的值
+func (s Symmetry) IsValid() bool { return s >= Rotate && s < symmetryEnd }
+
+func (s Symmetry) String() string {
+	if !s.IsValid() {
+		return "<unknown>"
+	}
+	return symmetryNames[s]
+}
+
+// 九个方块在尺寸为 size 的图像中所占的区域
+//
+// 当 padding 不能整除时，方块可能会超出图像的范围，返回值仅包含图像之内的部分。
+func area(size int) image.Rectangle {
+	padding := (size % 6) / 2
+	r := image.Rect(padding, padding, padding+size/3*3, padding+size/3*3)
+	return r.Intersect(image.Rect(0, 0, size, size))
+}
+
+// 返回 x,y 在对称之后其值实际所在的点
+//
+// 镜像的部分从左、上以及对角线左下方的区域复制而来。
+func (s Symmetry) source(a image.Rectangle, x, y int) (int, int) {
+	switch s {
+	case MirrorX:
+		x = mirror(a.Min.X, a.Max.X, x)
+	case MirrorXY:
+		x = mirror(a.Min.X, a.Max.X, x)
+		y = mirror(a.Min.Y, a.Max.Y, y)
+	case Diagonal:
+		if x > y { // a 始终是正方形，且左上角位于对角线上。
+			x, y = y, x
+		}
+	}
+	return x, y
+}
+
+// 以 [min,max) 的中线为轴，将位于右侧的 v 映射到左侧。
+func mirror(min, max, v int) int {
+	if m := min + max - 1 - v; m < v {
+		return m
+	}
+	return v
+}
+
+// 对已经绘制的图像 p 进行对称处理
+func (s Symmetry) apply(p *image.Paletted, size int) {
+	if s == Rotate || s == None {
+		return
+	}
+
+	a := area(size)
+	r := a.Intersect(p.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if sx, sy := s.source(a, x, y); sx != x || sy != y {
+				p.SetColorIndex(x, y, p.ColorIndexAt(sx, sy))
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestSymmetry_IsValid(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.True(Rotate.IsValid()).
+		True(None.IsValid()).
+		False(symmetryEnd.IsValid()).
+		False(Symmetry(-1).IsValid())
+
+	a.Equal(Rotate.String(), "rotate").
+		Equal(None.String(), "none").
+		Equal(symmetryEnd.String(), "<unknown>")
+}
+
+func TestMirror(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(mirror(0, 4, 0), 0).
+		Equal(mirror(0, 4, 1), 1).
+		Equal(mirror(0, 4, 2), 1).
+		Equal(mirror(0, 4, 3), 0).
+		Equal(mirror(1, 4, 2), 2). // 奇数宽度，中线不变
+		Equal(mirror(1, 4, 3), 1)
+}
+
+func TestDrawBlocks_symmetry(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	draw := func(s int, sum uint32, v Version, sym Symmetry) *image.Paletted {
+		img := image.NewPaletted(image.Rect(0, 0, s, s), p)
+		DrawBlocks(img, s, sum, v, sym)
+		return img
+	}
+
+	// 检测 img 中所有的点是否满足 f
+	invariant := func(img *image.Paletted, s int, f func(x, y int) (int, int)) {
+		a.TB().Helper()
+		r := area(s)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				xx, yy := f(x, y)
+				a.Equal(img.ColorIndexAt(x, y), img.ColorIndexAt(xx, yy), "x=%d,y=%d", x, y)
+			}
+		}
+	}
+
+	for _, s := range []int{MinSize, 99, size} {
+		r := area(s)
+		for _, v := range []Version{V1, V2} {
+			for i := 0; i < 10; i++ {
+				sum := uint32(11132323 + i*7919)
+
+				// Rotate 为默认值，与原有的输出相同。
+				a.Equal(draw(s, sum, v, Rotate).Pix, draw(s, sum, v, 0).Pix)
+
+				invariant(draw(s, sum, v, MirrorX), s, func(x, y int) (int, int) {
+					return r.Min.X + r.Max.X - 1 - x, y
+				})
+
+				img := draw(s, sum, v, MirrorXY)
+				invariant(img, s, func(x, y int) (int, int) {
+					return r.Min.X + r.Max.X - 1 - x, y
+				})
+				invariant(img, s, func(x, y int) (int, int) {
+					return x, r.Min.Y + r.Max.Y - 1 - y
+				})
+
+				invariant(draw(s, sum, v, Diagonal), s, func(x, y int) (int, int) {
+					return y, x
+				})
+
+				a.Equal(draw(s, sum, v, None).Pix, draw(s, sum, v, None).Pix)
+			}
+		}
+	}
+
+	// None 的方块和角度互不相关
+	var differs bool
+	for i := 0; i < 10; i++ {
+		sum := uint32(11132323 + i*7919)
+		if c := Choose(size, sum, V2, None); c != Choose(size, sum, V2, Rotate) {
+			differs = true
+			break
+		}
+	}
+	a.True(differs)
+
+	for sym := Rotate; sym < symmetryEnd; sym++ {
+		img := draw(size, 11132323, V2, sym)
+		fi, err := os.Create("./testdata/symmetry-" + strconv.Itoa(int(sym)) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+// Vector 以矢量的形式返回 DrawBlocks 绘制的内容
+//
+// 参数与 DrawBlocks 的参数相同。
+func Vector(size int, sum uint32, v Version, s Symmetry) []*vector.Layer {
+	p := &vector.Path{}
+	c := &canvas{Paletted: &image.Paletted{}, union: v != V1, path: p} // Paletted 的大小为空，不会绘制任何像素。
+	blockSize := size / 3
+	for _, pl := range layout(size, sum, v, s) {
+		c.start = p.Len()
+		blocks[pl.block](c, pl.x, pl.y, blockSize, pl.angle)
+	}
+	return s.layers(p, size)
+}
+
+// 根据对称方式将 p 拆分成多个图层
+//
+// 与 apply 相同，镜像的部分从左、上以及对角线左下方的区域复制而来。
+func (s Symmetry) layers(p *vector.Path, size int) []*vector.Layer {
+	a := area(size)
+	x0, y0, x1, y1 := float64(a.Min.X), float64(a.Min.Y), float64(a.Max.X), float64(a.Max.Y)
+	mx, my := (x0+x1)/2, (y0+y1)/2
+
+	rect := func(x0, y0, x1, y1 float64) *vector.Path {
+		clip := &vector.Path{}
+		clip.Rect(x0, y0, x1-x0, y1-y0)
+		return clip
+	}
+
+	switch s {
+	case MirrorX:
+		return []*vector.Layer{
+			{Path: p, Clip: rect(x0, y0, mx, y1)},
+			{Path: p, Clip: rect(mx, y0, x1, y1), Transform: &vector.Matrix{-1, 0, 0, 1, x0 + x1, 0}},
+		}
+	case MirrorXY:
+		return []*vector.Layer{
+			{Path: p, Clip: rect(x0, y0, mx, my)},
+			{Path: p, Clip: rect(mx, y0, x1, my), Transform: &vector.Matrix{-1, 0, 0, 1, x0 + x1, 0}},
+			{Path: p, Clip: rect(x0, my, mx, y1), Transform: &vector.Matrix{1, 0, 0, -1, 0, y0 + y1}},
+			{Path: p, Clip: rect(mx, my, x1, y1), Transform: &vector.Matrix{-1, 0, 0, -1, x0 + x1, y0 + y1}},
+		}
+	case Diagonal:
+		lower := &vector.Path{}
+		lower.Polygon(x0, y0, x1, y1, x0, y1)
+		upper := &vector.Path{}
+		upper.Polygon(x0, y0, x1, y0, x1, y1)
+		return []*vector.Layer{
+			{Path: p, Clip: lower},
+			{Path: p, Clip: upper, Transform: &vector.Matrix{0, 1, 1, 0, 0, 0}},
+		}
+	default:
+		return []*vector.Layer{vector.NewLayer(p)}
+	}
+}
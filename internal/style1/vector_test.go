@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style1
+
+import (
+	"image"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+func TestVector(t *testing.T) {
+	a := assert.New(t, false)
+
+	for sym, count := range map[Symmetry]int{Rotate: 1, MirrorX: 2, MirrorXY: 4, Diagonal: 2, None: 1} {
+		layers := Vector(size, 11132323, V2, sym)
+		a.Length(layers, count)
+		for _, l := range layers {
+			a.NotNil(l.Path).True(l.Path.Len() > 0)
+		}
+	}
+
+	// V1 中同一方块只保留最后一个多边形
+	const b8 = 8
+	for _, item := range []struct {
+		v        Version
+		polygons int
+	}{{v: V1, polygons: 1}, {v: V2, polygons: 3}} {
+		p := &vector.Path{}
+		c := &canvas{Paletted: &image.Paletted{}, union: item.v != V1, path: p}
+		blocks[b8](c, 0, 0, size, 0)
+
+		var closes int
+		for _, cmd := range p.Commands() {
+			if cmd.Op == vector.Close {
+				closes++
+			}
+		}
+		a.Equal(closes, item.polygons)
+	}
+
+	// b1 的矢量
+	p := &vector.Path{}
+	blocks[1](&canvas{Paletted: &image.Paletted{}, path: p}, 10, 20, 30, 0)
+	a.Equal(p.Commands()[0].Points[0], vector.Point{X: 10, Y: 20}).
+		Equal(p.Commands()[2].Points[0], vector.Point{X: 40, Y: 50})
+}
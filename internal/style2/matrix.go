@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style2
+
+import "fmt"
+
+// Matrix 由 hash 值生成的点阵
+//
+// 镜像时，点阵的左半部分由 hash 值逐位填充，右半部分为左半部分的镜像；
+// 否则所有的点都由 hash 值逐位填充。
+type Matrix struct {
+	blocks int
+	lines  []uint16
+}
+
+// Bits 生成 blocks*blocks 的点阵所需要的位数
+func Bits(blocks int, mirror bool) int {
+	if mirror {
+		return blocks * ((blocks + 1) / 2)
+	}
+	return blocks * blocks
+}
+
+// SumBits 将 sum 转换为 NewMatrix 的参数 bits
+func SumBits(sum uint32) []byte {
+	return []byte{byte(sum), byte(sum >> 8), byte(sum >> 16), byte(sum >> 24)}
+}
+
+// NewMatrix 根据 bits 生成 blocks*blocks 的点阵
+//
+// bits 中的每一位依次用于填充点阵，第 n 位为 bits[n/8] 从低位算起的第 n%8 位，
+// 其长度不能少于 Bits(blocks, mirror) 位；
+// mirror 是否将左半部分镜像到右半部分；
+func NewMatrix(bits []byte, blocks int, mirror bool) *Matrix {
+	if blocks < MinBlocks || blocks > MaxBlocks {
+		panic(fmt.Sprintf("参数 blocks 的值 %d 必须介于 [%d,%d] 之间", blocks, MinBlocks, MaxBlocks))
+	}
+	if l := Bits(blocks, mirror); len(bits)*8 < l {
+		panic(fmt.Sprintf("参数 bits 的长度不能少于 %d 位", l))
+	}
+
+	half := (blocks + 1) / 2
+	lines := make([]uint16, blocks)
+	var n int
+	for y := 0; y < blocks; y++ {
+		var line uint16
+		if mirror {
+			for x := half - 1; x >= 0; x-- {
+				if bits[n/8]>>(n%8)&1 == 1 {
+					line |= 1<<(blocks-1-x) | 1<<x // 第 x 列及其镜像
+				}
+				n++
+			}
+		} else {
+			for x := 0; x < blocks; x++ {
+				if bits[n/8]>>(n%8)&1 == 1 {
+					line |= 1 << (blocks - 1 - x)
+				}
+				n++
+			}
+		}
+		lines[y] = line
+	}
+
+	return &Matrix{blocks: blocks, lines: lines}
+}
+
+// Blocks 点阵的行数和列数
+func (m *Matrix) Blocks() int { return m.blocks }
+
+// Lines 点阵的内容
+//
+// 每个元素表示一行，从高位到低位依次表示从左到右的各个点，值为 1 表示前景色。
+func (m *Matrix) Lines() []uint16 { return m.lines }
+
+// ColorIndex 第 x 列第 y 行的点在调色板中的下标
+func (m *Matrix) ColorIndex(x, y int) uint8 {
+	if x < 0 || y < 0 || x >= m.blocks || y >= m.blocks {
+		return 0
+	}
+	return uint8(m.lines[y] >> (m.blocks - 1 - x) & 1)
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style2
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestBits(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Bits(5, true), 15).
+		Equal(Bits(6, true), 18).
+		Equal(Bits(7, true), 28).
+		Equal(Bits(8, true), 32).
+		Equal(Bits(10, true), 50).
+		Equal(Bits(12, true), 72).
+		Equal(Bits(8, false), 64).
+		Equal(Bits(16, false), 256)
+}
+
+func TestNewMatrix(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 8*8 的点阵与最初的实现保持一致
+	for i := 0; i < 100; i++ {
+		sum := uint32(123222243) | (uint32(i*7919) + 11133)
+		m := NewMatrix(SumBits(sum), Blocks, true)
+		a.Equal(m.Blocks(), Blocks)
+
+		var size int
+		for y, line := range m.Lines() {
+			vv := uint8((sum >> size & 0x0f)) << 4
+			a.Equal(line, uint16(vv+bits.Reverse8(vv)), "sum=%d,y=%d", sum, y)
+			size += 4
+		}
+	}
+
+	// 镜像
+	data := []byte("0123456789abcdefghijklmnopqrstuv")
+	for blocks := MinBlocks; blocks <= MaxBlocks; blocks++ {
+		m := NewMatrix(data, blocks, true)
+		a.Length(m.Lines(), blocks)
+		for y := 0; y < blocks; y++ {
+			a.Equal(m.Lines()[y]>>blocks, 0)
+			for x := 0; x < blocks; x++ {
+				a.Equal(m.ColorIndex(x, y), m.ColorIndex(blocks-1-x, y), "blocks=%d,x=%d,y=%d", blocks, x, y)
+			}
+		}
+	}
+
+	// 第 0 行依次为第 2、1、0 列，第 1 行依次为第 2、1、0 列……
+	m := NewMatrix([]byte{0b0010_0110, 0}, 5, true)
+	a.Equal(m.Lines()[0], 0b11011).
+		Equal(m.Lines()[1], 0b10001).
+		Equal(m.Lines()[2], 0).
+		Equal(m.ColorIndex(1, 0), 1).
+		Equal(m.ColorIndex(2, 0), 0).
+		Equal(m.ColorIndex(-1, 0), 0).
+		Equal(m.ColorIndex(5, 0), 0)
+
+	a.Panic(func() {
+		NewMatrix(data, MinBlocks-1, true)
+	})
+	a.Panic(func() {
+		NewMatrix(data, MaxBlocks+1, true)
+	})
+	a.Panic(func() {
+		NewMatrix(SumBits(1), 10, true)
+	})
+}
+
+func TestNewMatrix_asymmetric(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 每一位对应一个点
+	m := NewMatrix([]byte{0b0010_0110, 0b0000_0001, 0, 0}, 5, false)
+	a.Equal(m.Lines()[0], 0b01100).
+		Equal(m.Lines()[1], 0b10010).
+		Equal(m.Lines()[2], 0)
+
+	// 与镜像的结果不同
+	data := []byte("0123456789abcdefghijklmnopqrstuv")
+	for blocks := MinBlocks; blocks <= MaxBlocks; blocks++ {
+		m1 := NewMatrix(data, blocks, false)
+		m2 := NewMatrix(data, blocks, true)
+		a.NotEqual(m1.Lines(), m2.Lines()).Length(m1.Lines(), blocks)
+	}
+
+	a.Panic(func() {
+		NewMatrix(make([]byte, 8), 9, false)
+	})
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style2
+
+import "github.com/issue9/identicon/v2/internal/vector"
+
+// Shape 点阵中每个点的形状
+type Shape int8
+
+const (
+	Square        Shape = iota // 正方形，默认值。
+	Circle                     // 圆形
+	RoundedSquare              // 圆角正方形
+	Diamond                    // 菱形
+	Hexagon                    // 六边形
+	shapeEnd
+)
+
+// IsValid 是否为有效的值
+func (s Shape) IsValid() bool { return s >= Square && s < shapeEnd }
+
+// 返回边长为 bitsPerPoint 的点中各个像素是否需要填充
+//
+// gap 为相邻两点之间的间隔，每个点的形状位于扣除间隔之后的区域的中心。
+func (s Shape) mask(bitsPerPoint, gap int) []bool {
+	lo := gap / 2
+	d := bitsPerPoint - gap
+
+	ret := make([]bool, bitsPerPoint*bitsPerPoint)
+	for j := 0; j < d; j++ {
+		for i := 0; i < d; i++ {
+			ret[(j+lo)*bitsPerPoint+i+lo] = s.contains(i, j, d)
+		}
+	}
+	return ret
+}
+
+// 边长为 d 的区域中，i,j 处的像素是否位于形状之内
+//
+// 为了避免浮点运算，以像素的中心点进行判断，且所有坐标都放大一倍。
+func (s Shape) contains(i, j, d int) bool {
+	x, y := abs(2*i+1-d), abs(2*j+1-d)
+
+	switch s {
+	case Circle:
+		return x*x+y*y <= d*d
+	case RoundedSquare: // 圆角的半径为 d/4
+		r := d / 2
+		dx, dy := x-(d-r), y-(d-r)
+		if dx <= 0 || dy <= 0 {
+			return true
+		}
+		return dx*dx+dy*dy <= r*r
+	case Diamond:
+		return x+y <= d
+	case Hexagon: // 上下为平边，左右为顶点的六边形
+		return 2*x+y <= 2*d
+	default:
+		return true
+	}
+}
+
+// 将 x,y 处边长为 d 的形状添加至 p
+func (s Shape) path(p *vector.Path, x, y, d float64) {
+	switch s {
+	case Circle:
+		p.Circle(x+d/2, y+d/2, d/2)
+	case RoundedSquare:
+		p.RoundedRect(x, y, d, d, d/4)
+	case Diamond:
+		p.Polygon(x+d/2, y, x+d, y+d/2, x+d/2, y+d, x, y+d/2)
+	case Hexagon:
+		p.Polygon(x+d/4, y, x+3*d/4, y, x+d, y+d/2, x+3*d/4, y+d, x+d/4, y+d, x, y+d/2)
+	default:
+		p.Rect(x, y, d, d)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
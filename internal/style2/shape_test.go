@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style2
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestShape_mask(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.True(Square.IsValid()).
+		True(Hexagon.IsValid()).
+		False(shapeEnd.IsValid()).
+		False(Shape(-1).IsValid())
+
+	for _, v := range Square.mask(4, 0) {
+		a.True(v)
+	}
+
+	// 间隔平均分布在两侧
+	m := Square.mask(4, 2)
+	a.Equal(m, []bool{
+		false, false, false, false,
+		false, true, true, false,
+		false, true, true, false,
+		false, false, false, false,
+	})
+
+	a.Equal(Circle.mask(4, 0), []bool{
+		false, true, true, false,
+		true, true, true, true,
+		true, true, true, true,
+		false, true, true, false,
+	})
+
+	a.Equal(Diamond.mask(4, 0), []bool{
+		false, true, true, false,
+		true, true, true, true,
+		true, true, true, true,
+		false, true, true, false,
+	})
+
+	a.Equal(Diamond.mask(5, 0), []bool{
+		false, false, true, false, false,
+		false, true, true, true, false,
+		true, true, true, true, true,
+		false, true, true, true, false,
+		false, false, true, false, false,
+	})
+
+	a.Equal(Hexagon.mask(4, 0), []bool{
+		false, true, true, false,
+		true, true, true, true,
+		true, true, true, true,
+		false, true, true, false,
+	})
+
+	a.Equal(RoundedSquare.mask(8, 0), []bool{
+		false, true, true, true, true, true, true, false,
+		true, true, true, true, true, true, true, true,
+		true, true, true, true, true, true, true, true,
+		true, true, true, true, true, true, true, true,
+		true, true, true, true, true, true, true, true,
+		true, true, true, true, true, true, true, true,
+		true, true, true, true, true, true, true, true,
+		false, true, true, true, true, true, true, false,
+	})
+
+	// 所有形状都是上下左右对称的
+	for s := Square; s < shapeEnd; s++ {
+		for _, gap := range []int{0, 1, 4} {
+			const bpp = 16
+			m := s.mask(bpp, gap)
+			for y := 0; y < bpp; y++ {
+				for x := 0; x < bpp-gap%2; x++ { // 间隔为奇数时，右侧会多一个像素。
+					a.Equal(m[y*bpp+x], m[y*bpp+bpp-1-gap%2-x], "shape=%d,gap=%d,x=%d,y=%d", s, gap, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestMatrix_Draw_shape(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+	m := NewMatrix([]byte("0123456789abcdefghijklmnopqrstuv"), Blocks, true)
+
+	for s := Square; s < shapeEnd; s++ {
+		for _, gap := range []int{0, 3} {
+			img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+			m.Draw(img, size/Blocks, s, gap)
+
+			l := NewLazy(m, size/Blocks, s, gap)
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					a.Equal(l.ColorIndexAt(x, y), img.ColorIndexAt(x, y), "shape=%d,gap=%d,x=%d,y=%d", s, gap, x, y)
+				}
+			}
+
+			fi, err := os.Create("./testdata/shape-" + strconv.Itoa(int(s)) + "-" + strconv.Itoa(gap) + ".png")
+			a.NotError(err).NotNil(fi)
+			a.NotError(png.Encode(fi, img))
+			a.NotError(fi.Close()) // 关闭文件
+		}
+	}
+}
+
+func TestMatrix_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := NewMatrix([]byte{0b0010_0110, 0}, 5, true) // 11011,10001,00000...
+
+	// 同一行相邻的正方形合并
+	p := m.Path(10, Square, 0)
+	a.Equal(p.Len(), 4*5)
+
+	p = m.Path(10, Square, 2)
+	a.Equal(p.Len(), 6*5)
+
+	p = m.Path(10, Circle, 2)
+	a.Equal(p.Len(), 6*6)
+
+	p = m.Path(2, Circle, 2)
+	a.Equal(p.Len(), 0)
+}
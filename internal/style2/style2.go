@@ -7,28 +7,44 @@ package style2
 
 import (
 	"image"
-	"math/bits"
-)
 
-const Blocks = 8
+	"github.com/issue9/identicon/v2/internal/vector"
+)
 
-const half = Blocks / 2
+const (
+	Blocks    = 8  // 点阵默认的行数和列数
+	MinBlocks = 4  // 点阵最小的行数和列数
+	MaxBlocks = 16 // 点阵最大的行数和列数
+)
 
+// Draw 以默认大小的点阵绘制图像
+//
+// sum 由 hash 计算出的随机数；
 func Draw(p *image.Paletted, bitsPerPoint int, sum uint32) image.Image {
-	lines := matrix(sum)
+	NewMatrix(SumBits(sum), Blocks, true).Draw(p, bitsPerPoint, Square, 0)
+	return p
+}
+
+// Draw 将点阵绘制到 p 上
+//
+// bitsPerPoint 表示点阵中的每个点在 p 中的像素；
+// shape 每个点的形状；
+// gap 相邻两个点之间间隔的像素；
+func (m *Matrix) Draw(p *image.Paletted, bitsPerPoint int, shape Shape, gap int) {
+	mask := shape.mask(bitsPerPoint, gap)
 
 	var yBase, xBase int
-	for y := 0; y < Blocks; y++ {
-		line := lines[y]
+	for y := 0; y < m.blocks; y++ {
 		for yy := 0; yy < bitsPerPoint; yy++ {
-			for x := 0; x < Blocks; x++ {
-				var index uint8
-				if value := uint8(0b1000_0000 >> x); value&line == value {
-					index = 1
-				}
+			for x := 0; x < m.blocks; x++ {
+				index := m.ColorIndex(x, y)
 
 				for xx := 0; xx < bitsPerPoint; xx++ {
-					p.SetColorIndex(xBase+xx, yBase+yy, index)
+					if mask[yy*bitsPerPoint+xx] {
+						p.SetColorIndex(xBase+xx, yBase+yy, index)
+					} else {
+						p.SetColorIndex(xBase+xx, yBase+yy, 0)
+					}
 				}
 
 				xBase += bitsPerPoint
@@ -37,17 +53,64 @@ func Draw(p *image.Paletted, bitsPerPoint int, sum uint32) image.Image {
 		} // end yy
 		yBase += bitsPerPoint
 	}
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+//
+// 参数与 Draw 的参数相同。
+func (m *Matrix) Path(bitsPerPoint int, shape Shape, gap int) *vector.Path {
+	p := &vector.Path{}
+	bpp := float64(bitsPerPoint)
+	lo := float64(gap / 2)
+	d := float64(bitsPerPoint - gap)
+	if d <= 0 {
+		return p
+	}
+
+	for y := 0; y < m.blocks; y++ {
+		for x := 0; x < m.blocks; x++ {
+			if m.ColorIndex(x, y) == 0 {
+				continue
+			}
+
+			if shape == Square && gap == 0 { // 将同一行中相邻的点合并为一个矩形
+				start := x
+				for x+1 < m.blocks && m.ColorIndex(x+1, y) == 1 {
+					x++
+				}
+				p.Rect(float64(start)*bpp, float64(y)*bpp, float64(x-start+1)*bpp, bpp)
+				continue
+			}
+
+			shape.path(p, float64(x)*bpp+lo, float64(y)*bpp+lo, d)
+		}
+	}
 
 	return p
 }
 
-func matrix(v uint32) []uint8 {
-	ret := make([]uint8, 8)
-	var size int
-	for i := 0; i < Blocks; i++ {
-		vv := uint8((v >> size & 0x0f)) << half
-		ret[i] = vv + bits.Reverse8(vv)
-		size += half
+// Lazy 按需计算各个像素的值
+type Lazy struct {
+	bitsPerPoint int
+	matrix       *Matrix
+	mask         []bool
+}
+
+// NewLazy 声明 Lazy 对象
+//
+// 参数与 Matrix.Draw 的参数相同。
+func NewLazy(m *Matrix, bitsPerPoint int, shape Shape, gap int) *Lazy {
+	return &Lazy{
+		bitsPerPoint: bitsPerPoint,
+		matrix:       m,
+		mask:         shape.mask(bitsPerPoint, gap),
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (l *Lazy) ColorIndexAt(x, y int) uint8 {
+	if x < 0 || y < 0 || !l.mask[(y%l.bitsPerPoint)*l.bitsPerPoint+x%l.bitsPerPoint] {
+		return 0
 	}
-	return ret
+	return l.matrix.ColorIndex(x/l.bitsPerPoint, y/l.bitsPerPoint)
 }
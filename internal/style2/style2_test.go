@@ -38,3 +38,45 @@ func TestDraw(t *testing.T) {
 		a.NotError(fi.Close()) // 关闭文件
 	}
 }
+
+func TestLazy_ColorIndexAt(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 20; i++ {
+		sum := uint32(123222243) | (uint32(i) + 11133)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		Draw(img, size/Blocks, sum)
+
+		l := NewLazy(NewMatrix(SumBits(sum), Blocks, true), size/Blocks, Square, 0)
+		for y := -1; y <= size; y++ {
+			for x := -1; x <= size; x++ {
+				a.Equal(l.ColorIndexAt(x, y), img.ColorIndexAt(x, y), "sum=%d,x=%d,y=%d", sum, x, y)
+			}
+		}
+	}
+}
+
+func TestMatrix_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+	bits := []byte("0123456789abcdefghijklmnopqrstuv")
+
+	for blocks := MinBlocks; blocks <= MaxBlocks; blocks++ {
+		m := NewMatrix(bits, blocks, true)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		m.Draw(img, size/blocks, Square, 0)
+
+		l := NewLazy(m, size/blocks, Square, 0)
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				a.Equal(l.ColorIndexAt(x, y), img.ColorIndexAt(x, y), "blocks=%d,x=%d,y=%d", blocks, x, y)
+			}
+		}
+
+		fi, err := os.Create("./testdata/matrix-" + strconv.Itoa(blocks) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package style3 风格 3 的头像
+//
+// 将图像中间的正六边形按三角形网格分成 6*n*n 个小三角形，
+// 每个三角形的颜色由 hash 值中的一位决定，且呈 6 重或 3 重的旋转对称。
+//
+// 所有的计算均采用整数，以保证在不同平台上的输出完全相同。
+package style3
+
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+const (
+	MinSize = 28 // 图像的最小尺寸
+
+	n = 3 // 六边形的每条边被分成的段数
+)
+
+// 六边形中所有的三角形，按行从上到下，每行从左到右排列。
+var cells, cellIndexes = initCells()
+
+// 6 重和 3 重对称时，各个三角形所属的轨道，
+// 同一轨道中的三角形可以通过旋转相互重合，颜色也相同。
+var orbits = map[int][]int{6: initOrbits(6), 3: initOrbits(3)}
+
+// 三角形网格中的一个三角形
+//
+// 网格的两个基向量分别为 e1=(1,0) 和 e2=(1/2,√3/2)，
+// 以 P(a,b)=a*e1+b*e2 表示网格中的顶点，则：
+//   - t 为 0 时，顶点为 P(a,b)、P(a+1,b)、P(a,b+1)，尖角朝下；
+//   - t 为 1 时，顶点为 P(a+1,b)、P(a+1,b+1)、P(a,b+1)，尖角朝上；
+type cell struct {
+	a, b, t int
+}
+
+func initCells() ([]cell, *[2 * n][2 * n][2]int) {
+	ret := make([]cell, 0, 6*n*n)
+	indexes := &[2 * n][2 * n][2]int{}
+	for b := -n; b < n; b++ {
+		for a := -n; a < n; a++ {
+			for t := 0; t < 2; t++ {
+				indexes[a+n][b+n][t] = -1
+
+				c := cell{a: a, b: b, t: t}
+				if !c.inside() {
+					continue
+				}
+				indexes[a+n][b+n][t] = len(ret)
+				ret = append(ret, c)
+			}
+		}
+	}
+	return ret, indexes
+}
+
+// 计算 fold 重旋转对称时各个三角形所在的轨道
+func initOrbits(fold int) []int {
+	ret := make([]int, len(cells))
+	for i := range ret {
+		ret[i] = -1
+	}
+
+	var orbit int
+	for i, c := range cells {
+		if ret[i] >= 0 {
+			continue
+		}
+
+		for k := 0; k < fold; k++ {
+			ret[index(c)] = orbit
+			for j := 0; j < 6/fold; j++ {
+				c = c.rotate()
+			}
+		}
+		orbit++
+	}
+	return ret
+}
+
+func index(c cell) int { return cellIndexes[c.a+n][c.b+n][c.t] }
+
+// 三个顶点是否都在六边形之内
+func (c cell) inside() bool {
+	for _, p := range c.points() {
+		if abs(p[0]) > n || abs(p[1]) > n || abs(p[0]+p[1]) > n {
+			return false
+		}
+	}
+	return true
+}
+
+// 三个顶点在网格中的坐标
+func (c cell) points() [3][2]int {
+	if c.t == 0 {
+		return [3][2]int{{c.a, c.b}, {c.a + 1, c.b}, {c.a, c.b + 1}}
+	}
+	return [3][2]int{{c.a + 1, c.b}, {c.a + 1, c.b + 1}, {c.a, c.b + 1}}
+}
+
+// 以六边形的中心为原点旋转 60 度之后的三角形
+//
+// 以重心的三倍 (A,B) 表示三角形，旋转 60 度即 (A,B) => (-B,A+B)。
+func (c cell) rotate() cell {
+	aa, bb := 3*c.a+1+c.t, 3*c.b+1+c.t
+	aa, bb = -bb, aa+bb
+
+	r := cell{a: floorDiv(aa, 3), b: floorDiv(bb, 3)}
+	if aa-3*r.a == 2 {
+		r.t = 1
+	}
+	return r
+}
+
+// Grid 由三角形网格组成的六边形图案
+type Grid struct {
+	size   int
+	side   int // 三角形的边长，同时也是两倍坐标下三角形底边的一半。
+	height int // 两倍坐标下三角形的高，即 side*√3。
+	fold   int
+	fill   []bool // 与 cells 一一对应，表示各个三角形是否为前景色。
+}
+
+// NewGrid 声明 Grid 对象
+//
+// size 图像的尺寸；
+// sum 由 hash 计算出的随机数，最低位决定对称方式，其余各位依次对应各个轨道；
+func NewGrid(size int, sum uint32) *Grid {
+	side := size / (2*n + 1) // 左右各留出半个三角形的空白
+	g := &Grid{
+		size:   size,
+		side:   side,
+		height: (side*113512 + 32768) >> 16, // 113512 为 √3*65536 的近似值
+		fold:   6,
+	}
+	if sum&1 == 1 {
+		g.fold = 3
+	}
+
+	o := orbits[g.fold]
+	bits := sum >> 1
+	if bits&(uint32(1)<<(6*n*n/g.fold)-1) == 0 { // 避免空白的图案
+		bits = ^bits
+	}
+
+	g.fill = make([]bool, len(cells))
+	for i := range g.fill {
+		g.fill[i] = bits>>o[i]&1 == 1
+	}
+	return g
+}
+
+// Fold 旋转对称的重数，6 或是 3。
+func (g *Grid) Fold() int { return g.fold }
+
+// Rows 以行的形式返回各个三角形的颜色
+//
+// 每个元素表示一行，从左到右依次为该行的三角形，1 表示前景色，0 表示背景色。
+func (g *Grid) Rows() []string {
+	ret := make([]string, 0, 2*n)
+	row := make([]byte, 0, 4*n)
+	for i, c := range cells {
+		if g.fill[i] {
+			row = append(row, '1')
+		} else {
+			row = append(row, '0')
+		}
+
+		if i == len(cells)-1 || cells[i+1].b != c.b {
+			ret = append(ret, string(row))
+			row = row[:0]
+		}
+	}
+	return ret
+}
+
+// Draw 将图案绘制到 p 上
+func (g *Grid) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, g.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (g *Grid) ColorIndexAt(x, y int) uint8 {
+	// 以像素的中心点进行判断，为了避免浮点运算，所有坐标都放大一倍，且以图像的中心为原点。
+	xx, yy := 2*x+1-g.size, 2*y+1-g.size
+
+	// 将坐标转换为网格中的坐标 (a,b)，再根据小数部分之和是否小于 1 判断三角形的朝向。
+	denom := 2 * g.side * g.height
+	u := xx*g.height - yy*g.side
+	c := cell{a: floorDiv(u, denom), b: floorDiv(yy, g.height)}
+	if c.a < -n || c.a >= n || c.b < -n || c.b >= n {
+		return 0
+	}
+	if (u-c.a*denom)+(yy-c.b*g.height)*2*g.side >= denom {
+		c.t = 1
+	}
+
+	if i := index(c); i >= 0 && g.fill[i] {
+		return 1
+	}
+	return 0
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+//
+// 同一行中相邻的三角形会合并为一个多边形，以避免在缩放时出现缝隙。
+func (g *Grid) Path() *vector.Path {
+	p := &vector.Path{}
+	for i := 0; i < len(cells); i++ {
+		if !g.fill[i] {
+			continue
+		}
+
+		first := cells[i]
+		for i+1 < len(cells) && g.fill[i+1] && cells[i+1].b == first.b {
+			i++
+		}
+		last := cells[i]
+
+		b := first.b
+		left := first.a
+		if first.t == 1 {
+			left++
+		}
+		right := last.a
+		if last.t == 1 {
+			right++
+		}
+
+		points := make([]float64, 0, 8)
+		points = g.appendPoint(points, left, b)
+		points = g.appendPoint(points, last.a+1, b)
+		points = g.appendPoint(points, right, b+1)
+		points = g.appendPoint(points, first.a, b+1)
+		p.Polygon(points...)
+	}
+	return p
+}
+
+// 将网格中的顶点 P(a,b) 转换为图像中的坐标并添加到 points，与最后一个顶点相同时忽略。
+func (g *Grid) appendPoint(points []float64, a, b int) []float64 {
+	x := float64(g.size+(2*a+b)*g.side) / 2
+	y := float64(g.size+b*g.height) / 2
+	if l := len(points); l >= 2 && points[l-2] == x && points[l-1] == y {
+		return points
+	}
+	return append(points, x, y)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style3
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestCells(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Length(cells, 6*n*n)
+	for i, c := range cells {
+		a.Equal(index(c), i)
+
+		// 旋转 6 次之后回到原位，且始终位于六边形之内。
+		r := c
+		for k := 0; k < 6; k++ {
+			r = r.rotate()
+			a.True(r.inside(), "%+v", r)
+		}
+		a.Equal(r, c)
+	}
+
+	for fold, o := range orbits {
+		counts := map[int]int{}
+		for _, orbit := range o {
+			counts[orbit]++
+		}
+		a.Length(counts, 6*n*n/fold)
+		for orbit, count := range counts {
+			a.Equal(count, fold, "fold=%d,orbit=%d", fold, orbit)
+		}
+	}
+}
+
+func TestNewGrid(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(NewGrid(size, 0b10).Fold(), 6).
+		Equal(NewGrid(size, 0b11).Fold(), 3)
+
+	// 全为 0 时取反
+	g := NewGrid(size, 0)
+	for _, fill := range g.fill {
+		a.True(fill)
+	}
+
+	g = NewGrid(size, 0b110)
+	rows := g.Rows()
+	a.Length(rows, 2*n)
+	for i, l := range []int{7, 9, 11, 11, 9, 7} {
+		a.Length(rows[i], l)
+	}
+}
+
+func TestGrid_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 20; i++ {
+		sum := uint32(123222243) * (uint32(i) + 11133)
+		g := NewGrid(size, sum)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		g.Draw(img)
+
+		// 各个三角形重心处的颜色与 fill 一致
+		for index, c := range cells {
+			var x, y int
+			for _, pt := range c.points() {
+				x += g.size + (2*pt[0]+pt[1])*g.side
+				y += g.size + pt[1]*g.height
+			}
+			x, y = x/6, y/6
+			a.Equal(img.ColorIndexAt(x, y) == 1, g.fill[index], "sum=%d,cell=%+v", sum, c)
+		}
+
+		// 图像的四个角始终为背景色
+		a.Equal(img.ColorIndexAt(0, 0), 0).
+			Equal(img.ColorIndexAt(size-1, size-1), 0)
+
+		fi, err := os.Create("./testdata/hexagon-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
+
+func TestGrid_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 全部填充时，每行合并为一个多边形，上下两行为梯形，中间为五边形。
+	p := NewGrid(size, 0).Path()
+	var polygons int
+	for _, cmd := range p.Commands() {
+		if cmd.Op == vector.Close {
+			polygons++
+		}
+		for _, pt := range cmd.Points {
+			a.True(pt.X >= 0 && pt.X <= float64(size) && pt.Y >= 0 && pt.Y <= float64(size))
+		}
+	}
+	a.Equal(polygons, 2*n)
+
+	// 单个三角形
+	g := NewGrid(size, 0b10)
+	for i := range g.fill {
+		g.fill[i] = i == 0
+	}
+	a.Equal(g.Path().Len(), 4) // MoveTo、LineTo、LineTo、Close
+}
+
+func TestFloorDiv(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(floorDiv(7, 3), 2).
+		Equal(floorDiv(-7, 3), -3).
+		Equal(floorDiv(-6, 3), -2).
+		Equal(floorDiv(6, 3), 2).
+		Equal(floorDiv(0, 3), 0)
+}
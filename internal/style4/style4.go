@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package style4 风格 4 的头像
+//
+// 采用 Truchet 图块：将图像分成 Blocks*Blocks 个图块，
+// 所有图块采用相同的图案，但各自的旋转角度由 hash 值中的两位决定，
+// 相邻图块的线条首尾相接，形成连续的曲线或折线。
+package style4
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+const (
+	Blocks  = 6          // 每行和每列的图块数量
+	MinSize = 4 * Blocks // 图像的最小尺寸
+
+	// Bits 生成图像所需要的位数
+	//
+	// 前两位用于选择图块，之后每个图块两位，用于决定旋转的角度。
+	Bits = 2 + 2*Blocks*Blocks
+
+	sqrt2 = 1.4142135623730951
+)
+
+// Tile 图块的图案
+type Tile int8
+
+const (
+	Arc      Tile = iota // 连接相邻两边中点的两段四分之一圆弧
+	Diagonal             // 连接相邻两边中点的两条斜线
+	Triangle             // 沿对角线分割的三角形
+)
+
+// Grid 由图块组成的图案
+type Grid struct {
+	size    int
+	tile    int // 每个图块的像素
+	padding int // 不能整除时，边上的留白。
+	width   int // 线条的宽度
+	kind    Tile
+	angles  [Blocks * Blocks]uint8 // 各个图块顺时针旋转 90 度的次数
+}
+
+func (t Tile) String() string {
+	switch t {
+	case Arc:
+		return "arc"
+	case Diagonal:
+		return "diagonal"
+	case Triangle:
+		return "triangle"
+	default:
+		return fmt.Sprintf("Tile(%d)", t)
+	}
+}
+
+// NewGrid 根据 bits 生成尺寸为 size 的图案
+//
+// bits 中的第 n 位为 bits[n/8] 从低位算起的第 n%8 位，其长度不能少于 Bits 位。
+func NewGrid(bits []byte, size int) *Grid {
+	if len(bits)*8 < Bits {
+		panic(fmt.Sprintf("参数 bits 的长度不能少于 %d 位", Bits))
+	}
+
+	tile := size / Blocks
+	g := &Grid{
+		size:    size,
+		tile:    tile,
+		padding: (size - tile*Blocks) / 2,
+		width:   tile / 3,
+	}
+
+	bit := func(n int) uint8 { return bits[n/8] >> (n % 8) & 1 }
+	switch bit(0) | bit(1)<<1 {
+	case 2:
+		g.kind = Diagonal
+	case 3:
+		g.kind = Triangle
+	default: // 圆弧的效果最好，占一半的概率。
+		g.kind = Arc
+	}
+
+	for i := range g.angles {
+		g.angles[i] = bit(2+2*i) | bit(3+2*i)<<1
+	}
+	return g
+}
+
+// Tile 图块的图案
+func (g *Grid) Tile() Tile { return g.kind }
+
+// Angles 各个图块的旋转角度
+//
+// 每个元素表示一行，每个字符为对应图块顺时针旋转 90 度的次数。
+func (g *Grid) Angles() []string {
+	ret := make([]string, 0, Blocks)
+	for y := 0; y < Blocks; y++ {
+		row := make([]byte, 0, Blocks)
+		for x := 0; x < Blocks; x++ {
+			row = append(row, '0'+g.angles[y*Blocks+x])
+		}
+		ret = append(ret, string(row))
+	}
+	return ret
+}
+
+// Draw 将图案绘制到 p 上
+func (g *Grid) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, g.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (g *Grid) ColorIndexAt(x, y int) uint8 {
+	x -= g.padding
+	y -= g.padding
+	if x < 0 || y < 0 || x >= g.tile*Blocks || y >= g.tile*Blocks {
+		return 0
+	}
+
+	// 以像素的中心点进行判断，为了避免浮点运算，所有坐标都放大一倍。
+	s := 2 * g.tile
+	xx, yy := 2*(x%g.tile)+1, 2*(y%g.tile)+1
+
+	// 逆向旋转至 0 度时的位置
+	for angle := g.angles[(y/g.tile)*Blocks+x/g.tile]; angle > 0; angle-- {
+		xx, yy = yy, s-xx
+	}
+
+	if g.contains(xx, yy) {
+		return 1
+	}
+	return 0
+}
+
+// 在放大一倍的坐标系中，旋转角度为 0 的图块是否包含 x,y
+//
+// 圆弧和斜线的中心线都经过各边的中点，线条宽度为 width。
+func (g *Grid) contains(x, y int) bool {
+	s, w := 2*g.tile, g.width
+	switch g.kind {
+	case Arc: // 以左上角和右下角为圆心
+		r1, r2 := (s/2-w)*(s/2-w), (s/2+w)*(s/2+w)
+		d := x*x + y*y
+		if d >= r1 && d <= r2 {
+			return true
+		}
+		x, y = s-x, s-y
+		d = x*x + y*y
+		return d >= r1 && d <= r2
+	case Diagonal: // 线条与 x+y=s/2 的距离不超过 w/√2
+		d1, d2 := x+y-s/2, x+y-3*s/2
+		return d1*d1 <= 2*w*w || d2*d2 <= 2*w*w
+	default: // 左上角的一半
+		return x+y < s
+	}
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+func (g *Grid) Path() *vector.Path {
+	p := &vector.Path{}
+	t := float64(g.tile)
+	r1, r2 := (t-float64(g.width))/2, (t+float64(g.width))/2
+	d1 := (t - float64(g.width)*sqrt2) / 2
+	d2 := (t + float64(g.width)*sqrt2) / 2
+
+	for i, angle := range g.angles {
+		x := float64(g.padding + (i%Blocks)*g.tile)
+		y := float64(g.padding + (i/Blocks)*g.tile)
+
+		// 旋转之后的第 k 个角，从左上角开始顺时针依次编号。
+		corner := func(k int) (float64, float64) {
+			switch (k + int(angle)) % 4 {
+			case 0:
+				return x, y
+			case 1:
+				return x + t, y
+			case 2:
+				return x + t, y + t
+			default:
+				return x, y + t
+			}
+		}
+
+		// 以第 k 个角为原点，沿该角两条边分别前进 a 和 b 之后的点。
+		point := func(k int, a, b float64) []float64 {
+			cx, cy := corner(k)
+			nx, ny := corner(k + 1)
+			px, py := corner(k + 3)
+			return []float64{cx + (nx-cx)/t*a + (px-cx)/t*b, cy + (ny-cy)/t*a + (py-cy)/t*b}
+		}
+
+		switch g.kind {
+		case Arc:
+			for _, k := range []int{0, 2} {
+				cx, cy := corner(k)
+				p.Sector(cx, cy, r1, r2, (k+int(angle))%4)
+			}
+		case Diagonal:
+			for _, k := range []int{0, 2} {
+				points := make([]float64, 0, 8)
+				points = append(points, point(k, d1, 0)...)
+				points = append(points, point(k, d2, 0)...)
+				points = append(points, point(k, 0, d2)...)
+				points = append(points, point(k, 0, d1)...)
+				p.Polygon(points...)
+			}
+		default:
+			points := make([]float64, 0, 6)
+			points = append(points, point(0, 0, 0)...)
+			points = append(points, point(0, t, 0)...)
+			points = append(points, point(0, 0, t)...)
+			p.Polygon(points...)
+		}
+	}
+
+	return p
+}
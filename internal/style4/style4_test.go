@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style4
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestNewGrid(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Panic(func() {
+		NewGrid(make([]byte, Bits/8), size)
+	})
+
+	bits := make([]byte, (Bits+7)/8)
+	g := NewGrid(bits, size)
+	a.Equal(g.Tile(), Arc).
+		Equal(g.tile, 21).
+		Equal(g.padding, 1).
+		Equal(g.Angles()[0], "000000")
+
+	bits[0] = 0b0110
+	g = NewGrid(bits, size)
+	a.Equal(g.Tile(), Diagonal).
+		Equal(g.Tile().String(), "diagonal").
+		Equal(g.Angles()[0], "100000")
+
+	bits[0] = 0b1111
+	g = NewGrid(bits, size)
+	a.Equal(g.Tile(), Triangle).
+		Equal(g.Angles()[0], "300000").
+		Equal(Tile(10).String(), "Tile(10)")
+}
+
+func TestGrid_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 30; i++ {
+		bits := sha256.Sum256([]byte("truchet-" + strconv.Itoa(i)))
+		g := NewGrid(bits[:], size)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		g.Draw(img)
+
+		// 圆弧和斜线都经过每个图块各边的中点，所以相邻的图块能首尾相接。
+		if g.Tile() != Triangle {
+			for y := 0; y < Blocks; y++ {
+				for x := 0; x < Blocks; x++ {
+					left, top := g.padding+x*g.tile, g.padding+y*g.tile
+					mid, last := g.tile/2, g.tile-1
+					a.Equal(img.ColorIndexAt(left+mid, top), 1, "i=%d,x=%d,y=%d", i, x, y).
+						Equal(img.ColorIndexAt(left+mid, top+last), 1, "i=%d,x=%d,y=%d", i, x, y).
+						Equal(img.ColorIndexAt(left, top+mid), 1, "i=%d,x=%d,y=%d", i, x, y).
+						Equal(img.ColorIndexAt(left+last, top+mid), 1, "i=%d,x=%d,y=%d", i, x, y)
+				}
+			}
+		}
+
+		// 留白部分始终为背景色
+		a.Equal(img.ColorIndexAt(0, 0), 0).
+			Equal(img.ColorIndexAt(size-1, size-1), 0)
+
+		fi, err := os.Create("./testdata/truchet-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
+
+func TestGrid_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	bits := make([]byte, (Bits+7)/8)
+	for kind, count := range map[byte]int{0: 2, 2: 2, 3: 1} {
+		bits[0] = kind
+		g := NewGrid(bits, size)
+
+		var shapes int
+		for _, cmd := range g.Path().Commands() {
+			if cmd.Op == vector.Close {
+				shapes++
+			}
+			for _, pt := range cmd.Points {
+				a.True(pt.X >= 0 && pt.X <= float64(size) && pt.Y >= 0 && pt.Y <= float64(size))
+			}
+		}
+		a.Equal(shapes, count*Blocks*Blocks, "kind=%d", kind)
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package style5 风格 5 的头像
+//
+// 以 hash 值作为随机数的种子，采用深度优先的方式生成一个 Cells*Cells 的迷宫，
+// 迷宫中任意两个格子之间有且只有一条通路，墙壁为前景色。
+package style5
+
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/splitmix"
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+const (
+	Cells   = 6         // 迷宫每行和每列的格子数量
+	MinSize = 2 * units // 图像的最小尺寸
+
+	// 墙壁和格子的宽度分别为 1 和 2 个单位，整个迷宫的单位数。
+	units = 3*Cells + 1
+
+	// 以 (2*Cells+1)*(2*Cells+1) 的网格表示迷宫，
+	// 坐标均为奇数的是格子，均为偶数的是墙壁的交点，其它的为墙壁。
+	gridSize = 2*Cells + 1
+)
+
+// Maze 迷宫
+type Maze struct {
+	size    int
+	unit    int // 每个单位的像素
+	padding int // 不能整除时，边上的留白。
+	walls   [gridSize][gridSize]bool
+}
+
+// NewMaze 以 seed 为随机数种子生成尺寸为 size 的迷宫
+//
+// 入口位于左上角格子的上方，出口位于右下角格子的下方。
+func NewMaze(seed uint64, size int) *Maze {
+	unit := size / units
+	m := &Maze{
+		size:    size,
+		unit:    unit,
+		padding: (size - unit*units) / 2,
+	}
+
+	for y := 0; y < gridSize; y++ {
+		for x := 0; x < gridSize; x++ {
+			m.walls[y][x] = x%2 == 0 || y%2 == 0
+		}
+	}
+	m.walls[0][1] = false
+	m.walls[gridSize-1][gridSize-2] = false
+
+	// 深度优先遍历所有格子，每次随机选择一个未访问过的相邻格子，并打通两者之间的墙壁。
+	r := splitmix.Rand(seed)
+	var visited [Cells][Cells]bool
+	stack := make([][2]int, 1, Cells*Cells)
+	visited[0][0] = true
+	dirs := [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+
+		next := make([][2]int, 0, len(dirs))
+		for _, d := range dirs {
+			x, y := curr[0]+d[0], curr[1]+d[1]
+			if x >= 0 && y >= 0 && x < Cells && y < Cells && !visited[y][x] {
+				next = append(next, [2]int{x, y})
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		n := next[r.Intn(len(next))]
+		visited[n[1]][n[0]] = true
+		m.walls[curr[1]+n[1]+1][curr[0]+n[0]+1] = false // 两个格子在网格中的中点
+		stack = append(stack, n)
+	}
+
+	return m
+}
+
+// Rows 以行的形式返回迷宫的网格
+//
+// 每个元素表示一行，1 表示墙壁，0 表示通路。
+func (m *Maze) Rows() []string {
+	ret := make([]string, 0, gridSize)
+	for _, line := range m.walls {
+		row := make([]byte, 0, gridSize)
+		for _, wall := range line {
+			if wall {
+				row = append(row, '1')
+			} else {
+				row = append(row, '0')
+			}
+		}
+		ret = append(ret, string(row))
+	}
+	return ret
+}
+
+// Draw 将迷宫绘制到 p 上
+func (m *Maze) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, m.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (m *Maze) ColorIndexAt(x, y int) uint8 {
+	x -= m.padding
+	y -= m.padding
+	if x < 0 || y < 0 || x >= m.unit*units || y >= m.unit*units {
+		return 0
+	}
+
+	if m.walls[grid(y/m.unit)][grid(x/m.unit)] {
+		return 1
+	}
+	return 0
+}
+
+// 将第 u 个单位转换为网格中的坐标
+func grid(u int) int {
+	if u%3 == 0 {
+		return u / 3 * 2
+	}
+	return u/3*2 + 1
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+//
+// 同一行中相邻的墙壁会合并为一个矩形。
+func (m *Maze) Path() *vector.Path {
+	p := &vector.Path{}
+
+	// 网格中第 i 行或列的起始位置和宽度
+	offset := func(i int) (float64, float64) {
+		start := m.padding + (i/2*3+i%2)*m.unit
+		if i%2 == 0 {
+			return float64(start), float64(m.unit)
+		}
+		return float64(start), float64(2 * m.unit)
+	}
+
+	for y, line := range m.walls {
+		top, height := offset(y)
+		for x := 0; x < gridSize; x++ {
+			if !line[x] {
+				continue
+			}
+
+			left, _ := offset(x)
+			for x+1 < gridSize && line[x+1] {
+				x++
+			}
+			right, width := offset(x)
+			p.Rect(left, top, right+width-left, height)
+		}
+	}
+
+	return p
+}
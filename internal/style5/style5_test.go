@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style5
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestNewMaze(t *testing.T) {
+	a := assert.New(t, false)
+
+	for seed := uint64(0); seed < 500; seed++ {
+		m := NewMaze(seed*0x9e3779b97f4a7c15, size)
+
+		// 格子之间打通的墙壁数量为 Cells*Cells-1，且所有的格子都是连通的，即为一棵生成树。
+		var open int
+		for y := 1; y < gridSize-1; y++ {
+			for x := 1; x < gridSize-1; x++ {
+				if (x+y)%2 == 1 && !m.walls[y][x] {
+					open++
+				}
+			}
+		}
+		a.Equal(open, Cells*Cells-1, "seed=%d", seed)
+
+		var visited [Cells][Cells]bool
+		queue := [][2]int{{0, 0}}
+		visited[0][0] = true
+		count := 1
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			for _, d := range [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+				x, y := c[0]+d[0], c[1]+d[1]
+				if x < 0 || y < 0 || x >= Cells || y >= Cells || visited[y][x] {
+					continue
+				}
+				if m.walls[2*c[1]+1+d[1]][2*c[0]+1+d[0]] {
+					continue
+				}
+				visited[y][x] = true
+				count++
+				queue = append(queue, [2]int{x, y})
+			}
+		}
+		a.Equal(count, Cells*Cells, "seed=%d", seed)
+
+		// 相同的种子生成相同的迷宫
+		a.Equal(NewMaze(seed*0x9e3779b97f4a7c15, size).walls, m.walls)
+	}
+
+	rows := NewMaze(1, size).Rows()
+	a.Length(rows, gridSize).
+		Equal(rows[0], "1011111111111").
+		Equal(rows[gridSize-1], "1111111111101")
+}
+
+func TestMaze_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 20; i++ {
+		m := NewMaze(uint64(i)*0x2545f4914f6cdd1d, size)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		m.Draw(img)
+
+		// 迷宫范围内所有背景色的像素都是连通的
+		min, max := m.padding, m.padding+m.unit*units
+		start := image.Pt(min+m.unit, min+m.unit)
+		a.Equal(img.ColorIndexAt(start.X, start.Y), 0)
+		visited := map[image.Point]bool{start: true}
+		queue := []image.Point{start}
+		for len(queue) > 0 {
+			pt := queue[0]
+			queue = queue[1:]
+			for _, d := range []image.Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}} {
+				n := pt.Add(d)
+				if n.X < min || n.Y < min || n.X >= max || n.Y >= max || visited[n] || img.ColorIndexAt(n.X, n.Y) != 0 {
+					continue
+				}
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+		for y := min; y < max; y++ {
+			for x := min; x < max; x++ {
+				if img.ColorIndexAt(x, y) == 0 {
+					a.True(visited[image.Pt(x, y)], "i=%d,x=%d,y=%d", i, x, y)
+				}
+			}
+		}
+
+		fi, err := os.Create("./testdata/maze-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
+
+func TestMaze_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := NewMaze(1, size)
+	p := m.Path()
+	cmds := p.Commands()
+	a.True(len(cmds) > 0)
+	for _, cmd := range cmds {
+		for _, pt := range cmd.Points {
+			a.True(pt.X >= 0 && pt.X <= float64(size) && pt.Y >= 0 && pt.Y <= float64(size))
+		}
+	}
+
+	// 第一行为入口两侧的墙壁
+	a.Equal(cmds[0].Points[0].X, float64(m.padding)).
+		Equal(cmds[1].Points[0].X, float64(m.padding+m.unit)).
+		Equal(cmds[5].Points[0].X, float64(m.padding+3*m.unit))
+}
+
+func TestGrid(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(grid(0), 0).
+		Equal(grid(1), 1).
+		Equal(grid(2), 1).
+		Equal(grid(3), 2).
+		Equal(grid(units-1), gridSize-1)
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package style6 风格 6 的头像
+//
+// 采用一维的初等元胞自动机，由 hash 值决定规则和第一行的内容，
+// 之后的每一行都由上一行按规则演化而来，最终生成的点阵交由 style2 绘制。
+package style6
+
+import (
+	"fmt"
+
+	"github.com/issue9/identicon/v2/internal/style2"
+)
+
+const (
+	Blocks = style2.MaxBlocks // 点阵默认的行数和列数
+
+	ruleBits = 3 // 选择规则所用的位数
+)
+
+var (
+	// 镜像时可用的规则
+	//
+	// 这些规则左右对称，即 f(abc)=f(cba)，所以左右对称的第一行演化之后依然是对称的。
+	// 大部分规则生成的图案单调、呈周期性或是在某些第一行上很快就消失，
+	// 这里只挑选了图案较为丰富的部分，与 New 中依次尝试的机制相结合，
+	// 对于任意的第一行，都能找到不会出现全为 0 或全为 1 的行的规则。
+	// 比如规则 90 在对称的第一行上很快就会演化为全 0，所以并不在其中。
+	mirrorRules = [1 << ruleBits]uint8{105, 150, 147, 182, 151, 183, 73, 109}
+
+	// 不镜像时可用的规则
+	rules = [1 << ruleBits]uint8{30, 45, 75, 86, 101, 105, 110, 137}
+)
+
+// Automaton 一维的初等元胞自动机
+type Automaton struct {
+	rule   uint8
+	blocks int
+	lines  []uint16 // 每个元素表示一行，从高位到低位依次表示从左到右的各个元胞。
+}
+
+// New 根据 sum 生成 blocks 行和列的元胞自动机
+//
+// sum 的低 3 位用于选择规则，之后的各位依次为第一行从左到右的各个元胞；
+// mirror 为 true 时，第一行的右半部分为左半部分的镜像，且只采用左右对称的规则；
+//
+// 如果演化的过程中出现了全为 0 或全为 1 的行，之后的图案就会变得单调，
+// 此时会依次尝试规则列表中的下一个规则；如果所有的规则都不满足，则采用 sum 最初选择的规则。
+// 对于 [style2.MinBlocks,style2.MaxBlocks] 范围内任意的第一行，
+// 两个规则列表中都至少有一个满足条件的规则，测试中对此进行了穷举，所以实际上并不会用到最后的规则。
+func New(sum uint32, blocks int, mirror bool) *Automaton {
+	if blocks < style2.MinBlocks || blocks > style2.MaxBlocks {
+		panic(fmt.Sprintf("参数 blocks 的值 %d 必须介于 [%d,%d] 之间", blocks, style2.MinBlocks, style2.MaxBlocks))
+	}
+
+	table := rules
+	if mirror {
+		table = mirrorRules
+	}
+	index := int(sum & (1<<ruleBits - 1))
+	sum >>= ruleBits
+
+	var first uint16
+	if mirror {
+		for x := 0; x < (blocks+1)/2; x++ {
+			if sum>>x&1 == 1 {
+				first |= 1<<(blocks-1-x) | 1<<x
+			}
+		}
+	} else {
+		for x := 0; x < blocks; x++ {
+			if sum>>x&1 == 1 {
+				first |= 1 << (blocks - 1 - x)
+			}
+		}
+	}
+	full := uint16(1<<blocks - 1)
+	if first == 0 || first == full { // 全为 0 或全为 1 时，任何规则都只能生成单调的图案。
+		first = 1<<(blocks/2) | 1<<((blocks-1)/2)
+	}
+
+	a := &Automaton{blocks: blocks, lines: make([]uint16, blocks)}
+	a.lines[0] = first
+	for k := 0; k < len(table); k++ {
+		a.rule = table[(index+k)%len(table)]
+		if a.evolve(full) {
+			return a
+		}
+	}
+
+	a.rule = table[index]
+	a.evolve(full)
+	return a
+}
+
+// 从第一行开始依次演化之后的各行
+//
+// 返回值表示之后的各行是否都不全为 0 或是全为 1。
+func (a *Automaton) evolve(full uint16) bool {
+	ok := true
+	for y := 1; y < a.blocks; y++ {
+		a.lines[y] = a.step(a.lines[y-1])
+		if a.lines[y] == 0 || a.lines[y] == full {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// 根据规则计算 line 的下一行
+//
+// 首尾两个元胞互为邻居。
+func (a *Automaton) step(line uint16) uint16 {
+	var next uint16
+	for i := 0; i < a.blocks; i++ {
+		left := line >> ((i + 1) % a.blocks) & 1
+		center := line >> i & 1
+		right := line >> ((i + a.blocks - 1) % a.blocks) & 1
+		if a.rule>>(left<<2|center<<1|right)&1 == 1 {
+			next |= 1 << i
+		}
+	}
+	return next
+}
+
+// Rule 采用的规则编号
+func (a *Automaton) Rule() uint8 { return a.rule }
+
+// Matrix 将演化的结果转换为 style2 的点阵
+func (a *Automaton) Matrix() *style2.Matrix {
+	bits := make([]byte, (a.blocks*a.blocks+7)/8)
+	var n int
+	for _, line := range a.lines {
+		for x := 0; x < a.blocks; x++ {
+			if line>>(a.blocks-1-x)&1 == 1 {
+				bits[n/8] |= 1 << (n % 8)
+			}
+			n++
+		}
+	}
+	return style2.NewMatrix(bits, a.blocks, false)
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style6
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/identicon/v2/internal/style2"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestRules(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, r := range mirrorRules {
+		// f(110)=f(011) 且 f(100)=f(001)
+		a.Equal(r>>6&1, r>>3&1, "rule=%d", r).
+			Equal(r>>4&1, r>>1&1, "rule=%d", r)
+	}
+}
+
+func TestNew_evolve(t *testing.T) {
+	// 穷举所有的尺寸和第一行，演化之后都不会出现全为 0 或全为 1 的行，即不会用到 New 中最后的规则。
+	test := func(blocks int, mirror bool, rows uint32) {
+		full := uint16(1<<blocks - 1)
+		for row := uint32(0); row < rows; row++ {
+			for i := uint32(0); i < 1<<ruleBits; i++ {
+				at := New(row<<ruleBits|i, blocks, mirror)
+				for y, line := range at.lines {
+					if line == 0 || line == full {
+						t.Fatalf("blocks=%d,mirror=%v,rule=%d,row=%b,y=%d", blocks, mirror, at.Rule(), row, y)
+					}
+				}
+			}
+		}
+	}
+
+	for blocks := style2.MinBlocks; blocks <= style2.MaxBlocks; blocks++ {
+		test(blocks, true, 1<<((blocks+1)/2))
+		test(blocks, false, 1<<blocks)
+	}
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Panic(func() {
+		New(0, style2.MinBlocks-1, true)
+	})
+
+	// 规则 105，第一行全为 0 时，改为中间的两个元胞。
+	at := New(0, 8, true)
+	a.Equal(at.Rule(), 105).
+		Equal(at.lines[:3], []uint16{0b00011000, 0b11011011, 0b01111110})
+
+	// 规则 30，第一行为 10000000，首尾相接。
+	at = New(0b1_000, 8, false)
+	a.Equal(at.Rule(), 30).
+		Equal(at.lines[:3], []uint16{0b10000000, 0b11000001, 0b00100011})
+
+	for i := uint32(0); i < 1000; i++ {
+		sum := i * 0x9e3779b1
+		at := New(sum, Blocks, true)
+		for _, line := range at.lines {
+			var reverse uint16
+			for x := 0; x < Blocks; x++ {
+				reverse |= (line >> x & 1) << (Blocks - 1 - x)
+			}
+			a.Equal(reverse, line, "sum=%d", sum)
+		}
+	}
+}
+
+func TestAutomaton_Matrix(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 20; i++ {
+		at := New(uint32(i)*0x9e3779b1, Blocks, i%4 != 0)
+		m := at.Matrix()
+		a.Equal(m.Lines(), at.lines)
+
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		m.Draw(img, size/Blocks, style2.Square, 0)
+
+		fi, err := os.Create("./testdata/automaton-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package style7 风格 7 的头像
+//
+// 将图像中间的圆分成 Rings 个同心的圆环，每个圆环再平均分成 N 个扇区，
+// 每个圆环由 hash 值选择一种图案，并在所有扇区中重复，整体呈 N 重旋转对称，
+// 且每个扇区自身左右对称。N 同样由 hash 值决定，可以是 5、6、8 和 12。
+//
+// 光栅化时只采用整数运算，所需的三角函数值均为预先计算好的常量，
+// 以保证在不同平台上的输出完全相同。
+package style7
+
+import (
+	"image"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+const (
+	MinSize = 32 // 图像的最小尺寸
+	Rings   = 4  // 圆环的数量，包含中间的圆。
+
+	// MaxSize 图像的最大尺寸
+	//
+	// 光栅化时的乘积最大约为 1.6*size²*scale²，超过此尺寸可能会超出 int64 的范围。
+	MaxSize = 1 << 14
+
+	scale = 1 << 16 // 三角函数值的放大倍数
+)
+
+// Motif 圆环中的图案
+type Motif int8
+
+const (
+	Empty     Motif = iota // 空白
+	Band                   // 填满整个圆环
+	Spoke                  // 沿扇区中线的辐条
+	PetalOut               // 底边与内圆相切，尖角在外圆上的三角形
+	PetalIn                // 尖角在内圆上，底边两端在外圆上的三角形
+	Dot                    // 位于扇区中线上的圆点
+	Thin                   // 位于圆环中间的细环
+	BorderDot              // 位于扇区边界上的圆点
+)
+
+var motifNames = [...]string{"empty", "band", "spoke", "petal-out", "petal-in", "dot", "thin", "border-dot"}
+
+func (m Motif) String() string {
+	if m < 0 || int(m) >= len(motifNames) {
+		return "<unknown>"
+	}
+	return motifNames[m]
+}
+
+// 旋转对称的重数及其对应的三角函数值
+type fold struct {
+	n int
+
+	// 各个扇区中线的方向，即 (cos,sin)*scale，第一个扇区朝上，之后依次顺时针排列。
+	centers [][2]int64
+
+	// 扇区的半角，即 (cos(π/n),sin(π/n))*scale。
+	half [2]int64
+}
+
+var folds = [4]*fold{
+	{
+		n:       5,
+		half:    [2]int64{53020, 38521},
+		centers: [][2]int64{{0, -65536}, {62328, -20252}, {38521, 53020}, {-38521, 53020}, {-62328, -20252}},
+	},
+	{
+		n:       6,
+		half:    [2]int64{56756, 32768},
+		centers: [][2]int64{{0, -65536}, {56756, -32768}, {56756, 32768}, {0, 65536}, {-56756, 32768}, {-56756, -32768}},
+	},
+	{
+		n:    8,
+		half: [2]int64{60547, 25080},
+		centers: [][2]int64{
+			{0, -65536}, {46341, -46341}, {65536, 0}, {46341, 46341},
+			{0, 65536}, {-46341, 46341}, {-65536, 0}, {-46341, -46341},
+		},
+	},
+	{
+		n:    12,
+		half: [2]int64{63303, 16962},
+		centers: [][2]int64{
+			{0, -65536}, {32768, -56756}, {56756, -32768}, {65536, 0}, {56756, 32768}, {32768, 56756},
+			{0, 65536}, {-32768, 56756}, {-56756, 32768}, {-65536, 0}, {-56756, -32768}, {-32768, -56756},
+		},
+	},
+}
+
+// 中间的圆可以采用的图案
+//
+// 中间的圆没有内侧，PetalIn 即为正 N 边形，其它与内侧相关的图案没有意义。
+var centerMotifs = [4]Motif{Band, Thin, PetalIn, Empty}
+
+// Mandala 由同心圆环组成的图案
+//
+// 所有长度都采用放大一倍的坐标，以图像的中心为原点。
+type Mandala struct {
+	size   int
+	fold   *fold
+	width  int64 // 每个圆环的宽度
+	motifs [Rings]Motif
+}
+
+// NewMandala 声明 Mandala 对象
+//
+// size 图像的尺寸；
+// sum 由 hash 计算出的随机数，最低 2 位决定对称的重数，之后 2 位决定中间的圆，
+// 再之后每个圆环 3 位，从内到外依次决定各个圆环的图案；
+func NewMandala(size int, sum uint32) *Mandala {
+	m := &Mandala{
+		size:  size,
+		fold:  folds[sum&0b11],
+		width: int64(size*7/8) / Rings, // 外侧留出 1/16 的空白
+	}
+
+	m.motifs[0] = centerMotifs[sum>>2&0b11]
+	empty := m.motifs[0] == Empty
+	for i := 1; i < Rings; i++ {
+		m.motifs[i] = Motif(sum >> (4 + 3*(i-1)) & 0b111)
+		empty = empty && m.motifs[i] == Empty
+	}
+	if empty { // 避免空白的图案
+		m.motifs[0] = Band
+	}
+
+	return m
+}
+
+// N 旋转对称的重数
+func (m *Mandala) N() int { return m.fold.n }
+
+// Motifs 从内到外各个圆环的图案
+func (m *Mandala) Motifs() [Rings]Motif { return m.motifs }
+
+// Draw 将图案绘制到 p 上
+func (m *Mandala) Draw(p *image.Paletted) {
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			p.SetColorIndex(x, y, m.ColorIndexAt(x, y))
+		}
+	}
+}
+
+// ColorIndexAt 返回 x,y 处的颜色在调色板中的下标
+func (m *Mandala) ColorIndexAt(x, y int) uint8 {
+	// 以像素的中心点进行判断
+	xx, yy := int64(2*x+1-m.size), int64(2*y+1-m.size)
+	d := xx*xx + yy*yy
+
+	ring := -1
+	for i := 0; i < Rings; i++ {
+		if r := int64(i+1) * m.width; d < r*r {
+			ring = i
+			break
+		}
+	}
+	if ring < 0 {
+		return 0
+	}
+
+	// 距离最近的中线所在的扇区即为该点所在的扇区，
+	// along 和 across 分别为该点在中线方向上的投影以及到中线的距离，均放大了 scale 倍。
+	var along, across int64
+	for k, c := range m.fold.centers {
+		if a := xx*c[0] + yy*c[1]; k == 0 || a > along {
+			along = a
+			across = abs(xx*c[1] - yy*c[0])
+		}
+	}
+
+	if m.contains(ring, d, along, across) {
+		return 1
+	}
+	return 0
+}
+
+// 第 ring 个圆环中的图案是否包含指定的点
+//
+// d 为该点到中心距离的平方，along 和 across 参考 ColorIndexAt。
+func (m *Mandala) contains(ring int, d, along, across int64) bool {
+	inner, outer := int64(ring)*m.width, int64(ring+1)*m.width
+	mid := inner + m.width/2
+	hc, hs := m.fold.half[0], m.fold.half[1]
+
+	switch m.motifs[ring] {
+	case Band:
+		return true
+	case Spoke:
+		return across <= m.width/8*scale
+	case PetalOut: // 底边两端位于扇区的边界上，宽度随着 along 线性减小。
+		if along < inner*scale {
+			return false
+		}
+		return across*hc*(outer-inner) <= inner*hs*(outer*scale-along)
+	case PetalIn: // 位于尖角至底边两端连线的内侧，且不超过底边。
+		ax := inner * scale
+		bx, by := outer*hc, outer*hs
+		return along <= bx && (bx-ax)*across-by*(along-ax) <= 0
+	case Dot:
+		r := m.width / 3 * scale
+		dx := along - mid*scale
+		return dx*dx+across*across <= r*r
+	case Thin:
+		w := m.width / 6
+		return d >= (mid-w)*(mid-w) && d <= (mid+w)*(mid+w)
+	case BorderDot:
+		r := m.width / 3 * scale
+		dx, dy := along-mid*hc, across-mid*hs
+		return dx*dx+dy*dy <= r*r
+	default:
+		return false
+	}
+}
+
+// Path 以矢量的形式返回 Draw 绘制的内容
+func (m *Mandala) Path() *vector.Path {
+	p := &vector.Path{}
+	c := float64(m.size) / 2
+
+	// 将第 k 个扇区中的局部坐标转换为图像中的坐标，所有长度均为放大一倍的值。
+	point := func(k int, along, across float64) []float64 {
+		cx, cy := float64(m.fold.centers[k][0])/scale, float64(m.fold.centers[k][1])/scale
+		return []float64{c + (along*cx-across*cy)/2, c + (along*cy+across*cx)/2}
+	}
+	polygon := func(k int, points ...float64) {
+		ps := make([]float64, 0, len(points))
+		for i := 0; i < len(points); i += 2 {
+			ps = append(ps, point(k, points[i], points[i+1])...)
+		}
+		p.Polygon(ps...)
+	}
+
+	hc, hs := float64(m.fold.half[0])/scale, float64(m.fold.half[1])/scale
+	for ring, motif := range m.motifs {
+		inner, outer := float64(int64(ring)*m.width), float64(int64(ring+1)*m.width)
+		mid := float64(int64(ring)*m.width + m.width/2)
+
+		switch motif {
+		case Band:
+			p.Ring(c, c, inner/2, outer/2)
+			continue
+		case Thin:
+			w := float64(m.width / 6)
+			p.Ring(c, c, (mid-w)/2, (mid+w)/2)
+			continue
+		}
+
+		for k := 0; k < m.fold.n; k++ {
+			switch motif {
+			case Spoke:
+				w := float64(m.width / 8)
+				polygon(k, inner, -w, outer, -w, outer, w, inner, w)
+			case PetalOut:
+				polygon(k, inner, -inner*hs/hc, outer, 0, inner, inner*hs/hc)
+			case PetalIn:
+				polygon(k, inner, 0, outer*hc, outer*hs, outer*hc, -outer*hs)
+			case Dot:
+				xy := point(k, mid, 0)
+				p.Circle(xy[0], xy[1], float64(m.width/3)/2)
+			case BorderDot:
+				xy := point(k, mid*hc, mid*hs)
+				p.Circle(xy[0], xy[1], float64(m.width/3)/2)
+			}
+		}
+	}
+
+	return p
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
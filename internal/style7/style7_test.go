@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package style7
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math/big"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+var (
+	back = color.RGBA{R: 255, G: 0, B: 0, A: 100}
+	fore = color.RGBA{R: 0, G: 255, B: 255, A: 100}
+	size = 128
+)
+
+func TestFolds(t *testing.T) {
+	a := assert.New(t, false)
+
+	unit := func(v [2]int64) {
+		l := v[0]*v[0] + v[1]*v[1]
+		a.True(l > (scale-2)*(scale-2) && l < (scale+2)*(scale+2), "%v", v)
+	}
+
+	for _, f := range folds {
+		a.Length(f.centers, f.n)
+		unit(f.half)
+		for _, c := range f.centers {
+			unit(c)
+		}
+
+		// 左右对称
+		for k := 1; k < f.n; k++ {
+			c, m := f.centers[k], f.centers[f.n-k]
+			a.Equal(c[0], -m[0]).Equal(c[1], m[1])
+		}
+	}
+}
+
+func TestNewMandala(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := NewMandala(size, 0b111_110_101_10_11)
+	a.Equal(m.N(), 12).
+		Equal(m.Motifs(), [Rings]Motif{PetalIn, Dot, Thin, BorderDot}).
+		Equal(m.width, 28)
+
+	// 全部为空白时，中间的圆改为 Band
+	m = NewMandala(size, 0b000_000_000_11_01)
+	a.Equal(m.N(), 6).
+		Equal(m.Motifs(), [Rings]Motif{Band, Empty, Empty, Empty})
+}
+
+func TestMandala_Draw(t *testing.T) {
+	a := assert.New(t, false)
+	p := []color.Color{back, fore}
+
+	for i := 0; i < 20; i++ {
+		sum := uint32(i) * 0x9e3779b1
+		m := NewMandala(size, sum)
+		img := image.NewPaletted(image.Rect(0, 0, size, size), p)
+		m.Draw(img)
+
+		var count int
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				// 以中线为轴左右对称
+				a.Equal(img.ColorIndexAt(x, y), img.ColorIndexAt(size-1-x, y), "sum=%d,x=%d,y=%d", sum, x, y)
+				if img.ColorIndexAt(x, y) == 1 {
+					count++
+				}
+			}
+		}
+		a.True(count > 0, "sum=%d", sum)
+
+		// 外侧的留白
+		a.Equal(img.ColorIndexAt(0, 0), 0).
+			Equal(img.ColorIndexAt(size/2, 0), 0)
+
+		fi, err := os.Create("./testdata/mandala-" + strconv.Itoa(i) + ".png")
+		a.NotError(err).NotNil(fi)
+		a.NotError(png.Encode(fi, img))
+		a.NotError(fi.Close()) // 关闭文件
+	}
+}
+
+func TestMandala_contains(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 中间的圆为 PetalIn 时即为正 N 边形，顶点方向在扇区的边界上。
+	m := NewMandala(size, 0b000_000_000_10_10)
+	a.Equal(m.N(), 8).Equal(m.Motifs()[0], PetalIn)
+	r := m.width * 62 / 100 // 略小于正八边形的边心距 cos(π/8)*width
+	a.Equal(m.ColorIndexAt(size/2, (size-int(r))/2), 1).
+		Equal(m.ColorIndexAt(size/2, (size-int(m.width))/2), 0)
+}
+
+// 以 big.Int 实现的 contains，用于验证 MaxSize 时不会溢出。
+func containsBig(m *Mandala, ring int, d, along, across int64) bool {
+	n := func(v int64) *big.Int { return big.NewInt(v) }
+	mul := func(v ...*big.Int) *big.Int {
+		r := n(1)
+		for _, x := range v {
+			r.Mul(r, x)
+		}
+		return r
+	}
+	sub := func(x, y *big.Int) *big.Int { return new(big.Int).Sub(x, y) }
+	add := func(x, y *big.Int) *big.Int { return new(big.Int).Add(x, y) }
+
+	inner, outer := int64(ring)*m.width, int64(ring+1)*m.width
+	mid := inner + m.width/2
+	hc, hs := m.fold.half[0], m.fold.half[1]
+	sc := n(scale)
+
+	switch m.motifs[ring] {
+	case Band:
+		return true
+	case Spoke:
+		return across <= m.width/8*scale
+	case PetalOut:
+		if along < inner*scale {
+			return false
+		}
+		return mul(n(across), n(hc), n(outer-inner)).Cmp(mul(n(inner), n(hs), sub(mul(n(outer), sc), n(along)))) <= 0
+	case PetalIn:
+		ax := mul(n(inner), sc)
+		bx, by := mul(n(outer), n(hc)), mul(n(outer), n(hs))
+		return n(along).Cmp(bx) <= 0 && sub(mul(sub(bx, ax), n(across)), mul(by, sub(n(along), ax))).Sign() <= 0
+	case Dot:
+		r := n(m.width / 3 * scale)
+		dx := sub(n(along), mul(n(mid), sc))
+		return add(mul(dx, dx), mul(n(across), n(across))).Cmp(mul(r, r)) <= 0
+	case Thin:
+		w := m.width / 6
+		return d >= (mid-w)*(mid-w) && d <= (mid+w)*(mid+w)
+	case BorderDot:
+		r := n(m.width / 3 * scale)
+		dx, dy := sub(n(along), mul(n(mid), n(hc))), sub(n(across), mul(n(mid), n(hs)))
+		return add(mul(dx, dx), mul(dy, dy)).Cmp(mul(r, r)) <= 0
+	default:
+		return false
+	}
+}
+
+func TestMandala_MaxSize(t *testing.T) {
+	a := assert.New(t, false)
+
+	for f := range folds {
+		for motif := Empty; motif <= BorderDot; motif++ {
+			m := NewMandala(MaxSize, uint32(f))
+			m.motifs = [Rings]Motif{motif, motif, motif, motif}
+
+			for y := 0; y < MaxSize; y += 251 {
+				for x := 0; x < MaxSize; x += 251 {
+					xx, yy := int64(2*x+1-MaxSize), int64(2*y+1-MaxSize)
+					d := xx*xx + yy*yy
+					var along, across int64
+					for k, c := range m.fold.centers {
+						if v := xx*c[0] + yy*c[1]; k == 0 || v > along {
+							along = v
+							across = abs(xx*c[1] - yy*c[0])
+						}
+					}
+
+					for ring := 0; ring < Rings; ring++ {
+						a.Equal(m.contains(ring, d, along, across), containsBig(m, ring, d, along, across),
+							"n=%d,motif=%s,ring=%d,x=%d,y=%d", m.N(), motif, ring, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMandala_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	for i := 0; i < 20; i++ {
+		m := NewMandala(size, uint32(i)*0x9e3779b1)
+		cmds := m.Path().Commands()
+		a.True(len(cmds) > 0)
+		for _, cmd := range cmds {
+			for _, pt := range cmd.Points {
+				a.True(pt.X >= 0 && pt.X <= float64(size) && pt.Y >= 0 && pt.Y <= float64(size))
+			}
+		}
+	}
+}
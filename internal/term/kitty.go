@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package term
+
+import (
+	"bufio"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+// kitty 规定每一段经过 base64 编码之后的数据不能超过 4096 字节
+const kittyChunk = 4096
+
+// Kitty 将 img 以 Kitty 图形协议的转义字符写入 w
+//
+// 采用未压缩的 32 位 RGBA 格式(f=32)传输像素，数据超过 4096 字节时分段传输。
+func Kitty(w io.Writer, img image.Image) error {
+	rect := img.Bounds()
+	pix := make([]byte, 0, rect.Dx()*rect.Dy()*4)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pix = append(pix, c.R, c.G, c.B, c.A)
+		}
+	}
+	data := base64.StdEncoding.EncodeToString(pix)
+
+	buf := bufio.NewWriter(w)
+	for i := 0; i == 0 || i < len(data); i += kittyChunk {
+		end := i + kittyChunk
+		if end > len(data) {
+			end = len(data)
+		}
+		more := "0"
+		if end < len(data) {
+			more = "1"
+		}
+
+		buf.WriteString("\x1b_G")
+		if i == 0 {
+			buf.WriteString("a=T,f=32,s=")
+			buf.WriteString(strconv.Itoa(rect.Dx()))
+			buf.WriteString(",v=")
+			buf.WriteString(strconv.Itoa(rect.Dy()))
+			buf.WriteByte(',')
+		}
+		buf.WriteString("m=")
+		buf.WriteString(more)
+		buf.WriteByte(';')
+		buf.WriteString(data[i:end])
+		buf.WriteString("\x1b\\")
+	}
+
+	return buf.Flush()
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package term
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestKitty(t *testing.T) {
+	a := assert.New(t, false)
+
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.NRGBA{R: 1, G: 2, B: 3, A: 4})
+	buf := &bytes.Buffer{}
+	a.NotError(Kitty(buf, img))
+	a.Equal(buf.String(), "\x1b_Ga=T,f=32,s=1,v=1,m=0;AQIDBA==\x1b\\")
+
+	// 32x32x4 字节的数据经过 base64 编码之后为 5464 字节，分为两段。
+	buf.Reset()
+	a.NotError(Kitty(buf, image.NewNRGBA(image.Rect(0, 0, 32, 32))))
+	chunks := strings.Split(strings.TrimSuffix(buf.String(), "\x1b\\"), "\x1b\\")
+	a.Length(chunks, 2).
+		Equal(chunks[0], "\x1b_Ga=T,f=32,s=32,v=32,m=1;"+strings.Repeat("A", kittyChunk)).
+		Equal(chunks[1], "\x1b_Gm=0;"+strings.Repeat("A", 5464-kittyChunk-2)+"==")
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package term
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+// Sixel 将 img 以 Sixel 的格式写入 w
+//
+// img 必须实现了 image.PalettedImage，且 ColorModel 为 color.Palette，比如 *image.Paletted。
+// 调色板中的颜色依次作为 Sixel 的颜色寄存器，完全透明的颜色不会被绘制，由终端的背景色代替。
+// 连续 4 个及以上相同的字符会采用 ! 进行压缩。
+func Sixel(w io.Writer, src image.Image) error {
+	img, ok := src.(image.PalettedImage)
+	if !ok {
+		return errors.New("图片必须实现 image.PalettedImage")
+	}
+	palette, ok := img.ColorModel().(color.Palette)
+	if !ok {
+		return errors.New("图片的 ColorModel 必须为 color.Palette")
+	}
+	if len(palette) > 256 {
+		return errors.New("调色板中的颜色数量不能大于 256")
+	}
+
+	rect := img.Bounds()
+	buf := bufio.NewWriter(w)
+
+	// P2 为 1 表示未绘制的像素保持背景色不变
+	buf.WriteString("\x1bP0;1;0q\"1;1;")
+	buf.WriteString(strconv.Itoa(rect.Dx()))
+	buf.WriteByte(';')
+	buf.WriteString(strconv.Itoa(rect.Dy()))
+
+	visible := make([]bool, len(palette))
+	for i, c := range palette {
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		if visible[i] = n.A > 0; !visible[i] {
+			continue
+		}
+
+		buf.WriteByte('#')
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteString(";2;")
+		buf.WriteString(strconv.Itoa(percent(n.R)))
+		buf.WriteByte(';')
+		buf.WriteString(strconv.Itoa(percent(n.G)))
+		buf.WriteByte(';')
+		buf.WriteString(strconv.Itoa(percent(n.B)))
+	}
+
+	row := make([]byte, rect.Dx())
+	for top := rect.Min.Y; top < rect.Max.Y; top += 6 {
+		if top > rect.Min.Y {
+			buf.WriteByte('-')
+		}
+
+		first := true
+		for index := range palette {
+			if !visible[index] {
+				continue
+			}
+
+			var used bool
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < rect.Max.Y; dy++ {
+					if int(img.ColorIndexAt(x, top+dy)) == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x-rect.Min.X] = '?' + bits
+				used = used || bits > 0
+			}
+			if !used {
+				continue
+			}
+
+			if !first {
+				buf.WriteByte('$')
+			}
+			first = false
+			buf.WriteByte('#')
+			buf.WriteString(strconv.Itoa(index))
+			writeSixels(buf, row)
+		}
+	}
+
+	buf.WriteString("\x1b\\")
+	return buf.Flush()
+}
+
+// 将 0-255 转换为 0-100
+func percent(v uint8) int { return (int(v)*100 + 127) / 255 }
+
+// 写入一行 sixel 字符，末尾的空白字符会被忽略。
+func writeSixels(buf *bufio.Writer, row []byte) {
+	end := len(row)
+	for end > 0 && row[end-1] == '?' {
+		end--
+	}
+
+	for i := 0; i < end; {
+		j := i + 1
+		for j < end && row[j] == row[i] {
+			j++
+		}
+
+		if n := j - i; n >= 4 {
+			buf.WriteByte('!')
+			buf.WriteString(strconv.Itoa(n))
+			buf.WriteByte(row[i])
+		} else {
+			for k := 0; k < n; k++ {
+				buf.WriteByte(row[i])
+			}
+		}
+		i = j
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package term
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestSixel(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 3x7 的图片，前景色位于对角线和左下角。
+	img := image.NewPaletted(image.Rect(0, 0, 3, 7), color.Palette{back, fore})
+	img.SetColorIndex(0, 0, 1)
+	img.SetColorIndex(1, 1, 1)
+	img.SetColorIndex(2, 2, 1)
+	img.SetColorIndex(0, 6, 1)
+
+	buf := &bytes.Buffer{}
+	a.NotError(Sixel(buf, img))
+	a.Equal(buf.String(), "\x1bP0;1;0q\"1;1;3;7#0;2;100;0;0#1;2;0;100;100"+
+		"#0}|z$#1@AC-"+
+		"#0?@@$#1@"+
+		"\x1b\\")
+
+	// 透明的颜色以及压缩
+	img = image.NewPaletted(image.Rect(0, 0, 8, 1), color.Palette{color.Transparent, fore})
+	for x := 2; x < 8; x++ {
+		img.SetColorIndex(x, 0, 1)
+	}
+	buf.Reset()
+	a.NotError(Sixel(buf, img))
+	a.Equal(buf.String(), "\x1bP0;1;0q\"1;1;8;1#1;2;0;100;100#1??!6@\x1b\\")
+
+	// 非调色板的图片
+	a.Error(Sixel(buf, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+}
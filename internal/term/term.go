@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package term 将点阵转换为可在终端中显示的文本
+//
+// 支持 Unicode 的半块字符(▀▄)和盲文字符两种形式，
+// 颜色可以是 24 位真彩色、256 色或是不输出颜色的单色。
+package term
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Mode 颜色模式
+type Mode int8
+
+const (
+	TrueColor Mode = iota // 24 位真彩色
+	Color256              // 256 色
+	Mono                  // 单色，不输出任何转义字符。
+)
+
+const reset = "\x1b[0m"
+
+// Source 点阵中 x,y 处是否为前景色
+type Source func(x, y int) bool
+
+// HalfBlock 以半块字符输出 width*height 的点阵
+//
+// 每个字符表示上下两个点，fore 和 back 分别为前景色和背景色，
+// 背景色完全透明时不输出背景色，由终端的背景色代替。
+func HalfBlock(src Source, width, height int, fore, back color.Color, mode Mode) string {
+	var b strings.Builder
+	for y := 0; y < height; y += 2 {
+		begin(&b, fore, back, mode)
+		for x := 0; x < width; x++ {
+			top, bottom := src(x, y), y+1 < height && src(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		end(&b, mode)
+	}
+	return b.String()
+}
+
+// 盲文字符中 2x4 个点对应的位
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// Braille 以盲文字符输出 width*height 的点阵
+//
+// 每个字符表示 2x4 个点，超出点阵的部分视为背景色，其它与 HalfBlock 相同。
+func Braille(src Source, width, height int, fore, back color.Color, mode Mode) string {
+	var b strings.Builder
+	for y := 0; y < height; y += 4 {
+		begin(&b, fore, back, mode)
+		for x := 0; x < width; x += 2 {
+			r := rune(0x2800)
+			for dy, row := range brailleDots {
+				for dx, bit := range row {
+					if x+dx < width && y+dy < height && src(x+dx, y+dy) {
+						r |= bit
+					}
+				}
+			}
+			b.WriteRune(r)
+		}
+		end(&b, mode)
+	}
+	return b.String()
+}
+
+// 输出每一行开始时的颜色
+func begin(b *strings.Builder, fore, back color.Color, mode Mode) {
+	if mode == Mono {
+		return
+	}
+
+	if c := color.NRGBAModel.Convert(back).(color.NRGBA); c.A > 0 {
+		b.WriteString(escape(48, c, mode))
+	}
+	b.WriteString(escape(38, color.NRGBAModel.Convert(fore).(color.NRGBA), mode))
+}
+
+// 结束一行
+func end(b *strings.Builder, mode Mode) {
+	if mode != Mono {
+		b.WriteString(reset)
+	}
+	b.WriteByte('\n')
+}
+
+// 生成设置颜色的转义字符
+//
+// code 为 38 时表示前景色，48 表示背景色。
+func escape(code int, c color.NRGBA, mode Mode) string {
+	prefix := "\x1b[" + strconv.Itoa(code)
+	if mode == Color256 {
+		return prefix + ";5;" + strconv.Itoa(int(To256(c))) + "m"
+	}
+	return prefix + ";2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B)) + "m"
+}
+
+// 256 色中 6x6x6 色块每个分量的值
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// To256 返回与 c 最接近的 256 色的编号
+//
+// 仅在 16 至 255 之间选取，即 6x6x6 的色块和 24 级灰度，不采用因终端而异的前 16 种颜色。
+func To256(c color.NRGBA) uint8 {
+	r, g, b := int(c.R), int(c.G), int(c.B)
+
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := uint8(16 + 36*ri + 6*gi + bi)
+	cubeDist := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// 灰度为 8+10*i，i 取值 [0,23]
+	avg := (r + g + b) / 3
+	gi2 := (avg - 3) / 10
+	if gi2 < 0 {
+		gi2 = 0
+	} else if gi2 > 23 {
+		gi2 = 23
+	}
+	grey := 8 + 10*gi2
+	if distance(r, g, b, grey, grey, grey) < cubeDist {
+		return uint8(232 + gi2)
+	}
+	return cube
+}
+
+// 与 v 最接近的色块分量的下标
+func cubeIndex(v int) int {
+	if v < 48 {
+		return 0
+	}
+	if v < 115 {
+		return 1
+	}
+	return (v - 35) / 40
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	r, g, b := r1-r2, g1-g2, b1-b2
+	return r*r + g*g + b*b
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package term
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+var (
+	back = color.NRGBA{R: 255, G: 0, B: 0, A: 255}
+	fore = color.NRGBA{R: 0, G: 255, B: 255, A: 255}
+)
+
+// 3x3 的点阵
+//
+//	1 0 1
+//	0 1 1
+//	1 0 0
+func source(x, y int) bool {
+	return []string{"101", "011", "100"}[y][x] == '1'
+}
+
+func TestHalfBlock(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(HalfBlock(source, 3, 3, fore, back, Mono), "▀▄█\n▀  \n")
+
+	a.Equal(HalfBlock(source, 3, 3, fore, back, TrueColor),
+		"\x1b[48;2;255;0;0m\x1b[38;2;0;255;255m▀▄█\x1b[0m\n"+
+			"\x1b[48;2;255;0;0m\x1b[38;2;0;255;255m▀  \x1b[0m\n")
+
+	// 透明的背景色
+	a.Equal(HalfBlock(source, 3, 1, fore, color.Transparent, Color256),
+		"\x1b[38;5;51m▀ ▀\x1b[0m\n")
+}
+
+func TestBraille(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 第一个字符为 (0,0)、(1,1)、(0,2)，第二个字符为 (2,0)、(2,1)。
+	a.Equal(Braille(source, 3, 3, fore, back, Mono), string([]rune{0x2800 | 0x01 | 0x10 | 0x04, 0x2800 | 0x01 | 0x02})+"\n")
+	a.Equal(Braille(source, 0, 0, fore, back, Mono), "")
+}
+
+func TestTo256(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(To256(color.NRGBA{R: 255}), 196).
+		Equal(To256(color.NRGBA{G: 255, B: 255}), 51).
+		Equal(To256(color.NRGBA{R: 255, G: 255, B: 255}), 231).
+		Equal(To256(color.NRGBA{}), 16).
+		Equal(To256(color.NRGBA{R: 128, G: 128, B: 128}), 244).
+		Equal(To256(color.NRGBA{R: 95, G: 135, B: 175}), 16+36*1+6*2+3)
+}
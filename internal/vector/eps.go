@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+)
+
+// WriteEPS 将图像以 EPS 的格式输出到 w
+//
+// 图像的宽度由 p.Size 指定。
+func (img *Image) WriteEPS(w io.Writer, p *Print) error {
+	height := img.printHeight(p)
+	buf := bufio.NewWriter(w)
+
+	buf.WriteString("%!PS-Adobe-3.0 EPSF-3.0\n")
+	fmt.Fprintf(buf, "%%%%BoundingBox: 0 0 %d %d\n", int(math.Ceil(p.Size)), int(math.Ceil(height)))
+	fmt.Fprintf(buf, "%%%%HiResBoundingBox: 0 0 %s %s\n", formatFloat(p.Size), formatFloat(height))
+	buf.WriteString("%%LanguageLevel: 2\n%%Pages: 1\n%%EndComments\n")
+
+	s := p.Size / float64(img.Width)
+	fmt.Fprintf(buf, "gsave\n0 %s translate\n%s %s scale\n", formatFloat(height), formatFloat(s), formatFloat(-s))
+	if writeEPSColor(buf, img.Back, p) {
+		fmt.Fprintf(buf, "0 0 %d %d rectfill\n", img.Width, img.Height)
+	}
+	if writeEPSColor(buf, img.Fore, p) {
+		for _, l := range img.Layers {
+			if l.Path.Len() == 0 {
+				continue
+			}
+
+			buf.WriteString("gsave\n")
+			if l.Clip != nil {
+				buf.WriteString("newpath\n")
+				writePrintPath(buf, l.Clip, epsOps)
+				buf.WriteString("clip\n")
+			}
+			if m := l.Transform; m != nil {
+				fmt.Fprintf(buf, "[%s %s %s %s %s %s] concat\n",
+					formatFloat(m[0]), formatFloat(m[1]), formatFloat(m[2]),
+					formatFloat(m[3]), formatFloat(m[4]), formatFloat(m[5]))
+			}
+			buf.WriteString("newpath\n")
+			writePrintPath(buf, l.Path, epsOps)
+			buf.WriteString("fill\ngrestore\n")
+		}
+	}
+	buf.WriteString("grestore\nshowpage\n%%EOF\n")
+
+	return buf.Flush()
+}
+
+// 输出设置填充色的指令，如果颜色完全透明，则返回 false。
+func writeEPSColor(buf *bufio.Writer, c color.Color, p *Print) bool {
+	r, g, b, ok := printRGB(c)
+	if !ok {
+		return false
+	}
+
+	if p.Ink == nil {
+		fmt.Fprintf(buf, "%s %s %s setrgbcolor\n", formatFloat(r), formatFloat(g), formatFloat(b))
+		return true
+	}
+
+	ink := p.Ink(c)
+	if ink.Name == "" {
+		fmt.Fprintf(buf, "%s %s %s %s setcmykcolor\n", formatFloat(ink.C), formatFloat(ink.M), formatFloat(ink.Y), formatFloat(ink.K))
+		return true
+	}
+
+	fmt.Fprintf(buf, "[/Separation (%s) /DeviceCMYK {dup %s mul exch dup %s mul exch dup %s mul exch %s mul}] setcolorspace 1 setcolor\n",
+		psString(ink.Name), formatFloat(ink.C), formatFloat(ink.M), formatFloat(ink.Y), formatFloat(ink.K))
+	return true
+}
+
+// 转义 PostScript 字符串中的特殊字符
+func psString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
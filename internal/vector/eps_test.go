@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestImage_WriteEPS(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Polygon(0, 0, 10, 0, 0, 10)
+	img := &Image{
+		Width:  10,
+		Height: 20,
+		Back:   color.Transparent,
+		Fore:   color.NRGBA{G: 255, A: 255},
+		Layers: []*Layer{NewLayer(p)},
+	}
+
+	buf := &bytes.Buffer{}
+	a.NotError(img.WriteEPS(buf, &Print{Size: 28.35}))
+	a.Equal(buf.String(), "%!PS-Adobe-3.0 EPSF-3.0\n"+
+		"%%BoundingBox: 0 0 29 57\n"+
+		"%%HiResBoundingBox: 0 0 28.35 56.7\n"+
+		"%%LanguageLevel: 2\n%%Pages: 1\n%%EndComments\n"+
+		"gsave\n0 56.7 translate\n2.835 -2.835 scale\n"+
+		"0 1 0 setrgbcolor\n"+
+		"gsave\nnewpath\n0 0 moveto\n10 0 lineto\n0 10 lineto\nclosepath\nfill\ngrestore\n"+
+		"grestore\nshowpage\n%%EOF\n")
+
+	// CMYK 和专色，以及裁剪和变换。
+	img.Back = color.White
+	img.Layers = []*Layer{{Path: p, Clip: p, Transform: &Matrix{1, 0, 0, 1, 5, 0}}}
+	pr := &Print{
+		Size: 10,
+		Ink: func(c color.Color) Ink {
+			if c == color.White {
+				return Ink{}
+			}
+			return Ink{Name: "Gold (metallic)", C: 0.1, M: 0.2, Y: 0.8}
+		},
+	}
+	buf.Reset()
+	a.NotError(img.WriteEPS(buf, pr))
+	a.Contains(buf.String(), "0 0 0 0 setcmykcolor\n0 0 10 20 rectfill\n").
+		Contains(buf.String(), "[/Separation (Gold \\(metallic\\)) /DeviceCMYK {dup 0.1 mul exch dup 0.2 mul exch dup 0.8 mul exch 0 mul}] setcolorspace 1 setcolor\n").
+		Contains(buf.String(), "gsave\nnewpath\n0 0 moveto\n10 0 lineto\n0 10 lineto\nclosepath\nclip\n[1 0 0 1 5 0] concat\nnewpath\n")
+
+	fi, err := os.Create("./testdata/print.eps")
+	a.NotError(err).NotNil(fi)
+	_, err = buf.WriteTo(fi)
+	a.NotError(err)
+	a.NotError(fi.Close()) // 关闭文件
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+// WritePDF 将图像以单页 PDF 的格式输出到 w
+//
+// 页面的大小与图像相同，宽度由 p.Size 指定。内容流未经压缩，不依赖任何第三方库。
+func (img *Image) WritePDF(w io.Writer, p *Print) error {
+	height := img.printHeight(p)
+	spots := map[string]string{} // 专色的名称与其在资源中的名称
+	spotDefs := &bytes.Buffer{}
+
+	content := &bytes.Buffer{}
+	cb := bufio.NewWriter(content)
+	fill := func(c color.Color) bool {
+		return writePDFColor(cb, c, p, spots, spotDefs)
+	}
+
+	s := p.Size / float64(img.Width)
+	fmt.Fprintf(cb, "q\n%s 0 0 %s 0 %s cm\n", formatFloat(s), formatFloat(-s), formatFloat(height))
+	if fill(img.Back) {
+		fmt.Fprintf(cb, "0 0 %d %d re\nf\n", img.Width, img.Height)
+	}
+	if fill(img.Fore) {
+		for _, l := range img.Layers {
+			if l.Path.Len() == 0 {
+				continue
+			}
+
+			cb.WriteString("q\n")
+			if l.Clip != nil {
+				writePrintPath(cb, l.Clip, pdfOps)
+				cb.WriteString("W n\n")
+			}
+			if m := l.Transform; m != nil {
+				fmt.Fprintf(cb, "%s %s %s %s %s %s cm\n",
+					formatFloat(m[0]), formatFloat(m[1]), formatFloat(m[2]),
+					formatFloat(m[3]), formatFloat(m[4]), formatFloat(m[5]))
+			}
+			writePrintPath(cb, l.Path, pdfOps)
+			cb.WriteString("f\nQ\n")
+		}
+	}
+	cb.WriteString("Q\n")
+	if err := cb.Flush(); err != nil {
+		return err
+	}
+
+	resources := "<< >>"
+	if spotDefs.Len() > 0 {
+		resources = "<< /ColorSpace <<" + spotDefs.String() + " >> >>"
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 " + formatFloat(p.Size) + " " + formatFloat(height) + "] /Resources " + resources + " /Contents 4 0 R >>",
+		"<< /Length " + strconv.Itoa(content.Len()) + " >>\nstream\n" + content.String() + "endstream",
+	}
+
+	buf := bufio.NewWriter(w)
+	var offset int
+	write := func(s string) {
+		buf.WriteString(s)
+		offset += len(s)
+	}
+
+	write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, 0, len(objects))
+	for index, obj := range objects {
+		offsets = append(offsets, offset)
+		write(strconv.Itoa(index+1) + " 0 obj\n" + obj + "\nendobj\n")
+	}
+
+	xref := offset
+	write("xref\n0 " + strconv.Itoa(len(objects)+1) + "\n0000000000 65535 f \n")
+	for _, o := range offsets {
+		write(fmt.Sprintf("%010d 00000 n \n", o))
+	}
+	write("trailer\n<< /Size " + strconv.Itoa(len(objects)+1) + " /Root 1 0 R >>\nstartxref\n" + strconv.Itoa(xref) + "\n%%EOF\n")
+
+	return buf.Flush()
+}
+
+// 输出设置填充色的指令，如果颜色完全透明，则返回 false。
+//
+// 专色会以 /CS0、/CS1 等名称添加到 defs 中，spots 记录已经添加的专色。
+func writePDFColor(buf *bufio.Writer, c color.Color, p *Print, spots map[string]string, defs *bytes.Buffer) bool {
+	r, g, b, ok := printRGB(c)
+	if !ok {
+		return false
+	}
+
+	if p.Ink == nil {
+		fmt.Fprintf(buf, "%s %s %s rg\n", formatFloat(r), formatFloat(g), formatFloat(b))
+		return true
+	}
+
+	ink := p.Ink(c)
+	if ink.Name == "" {
+		fmt.Fprintf(buf, "%s %s %s %s k\n", formatFloat(ink.C), formatFloat(ink.M), formatFloat(ink.Y), formatFloat(ink.K))
+		return true
+	}
+
+	cs, found := spots[ink.Name]
+	if !found {
+		cs = "CS" + strconv.Itoa(len(spots))
+		spots[ink.Name] = cs
+		fmt.Fprintf(defs, " /%s [/Separation %s /DeviceCMYK << /FunctionType 2 /Domain [0 1] /C0 [0 0 0 0] /C1 [%s %s %s %s] /N 1 >>]",
+			cs, pdfName(ink.Name), formatFloat(ink.C), formatFloat(ink.M), formatFloat(ink.Y), formatFloat(ink.K))
+	}
+	fmt.Fprintf(buf, "/%s cs 1 scn\n", cs)
+	return true
+}
+
+// 将 s 转换为 PDF 中的名称对象
+//
+// 除字母和数字之外的字符均以 #xx 的形式表示。
+func pdfName(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "#%02X", c)
+		}
+	}
+	return b.String()
+}
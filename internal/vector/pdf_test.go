@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestImage_WritePDF(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Rect(0, 0, 5, 5)
+	img := &Image{
+		Width:  10,
+		Height: 10,
+		Back:   color.Transparent,
+		Fore:   color.NRGBA{R: 255, A: 128},
+		Layers: []*Layer{NewLayer(p), NewLayer(&Path{})},
+	}
+
+	buf := &bytes.Buffer{}
+	a.NotError(img.WritePDF(buf, &Print{Size: 72}))
+	pdf := buf.String()
+	a.True(strings.HasPrefix(pdf, "%PDF-1.4\n")).
+		True(strings.HasSuffix(pdf, "%%EOF\n")).
+		Contains(pdf, "/MediaBox [0 0 72 72] /Resources << >>").
+		Contains(pdf, "stream\nq\n7.2 0 0 -7.2 0 72 cm\n1 0 0 rg\nq\n0 0 m\n5 0 l\n5 5 l\n0 5 l\nh\nf\nQ\nQ\nendstream")
+	checkXref(a, pdf, 4)
+
+	// CMYK 和专色，以及裁剪和变换。
+	img.Back = color.White
+	img.Layers = []*Layer{{Path: p, Clip: p, Transform: &Matrix{1, 0, 0, 1, 5, 0}}}
+	pr := &Print{
+		Size: 30,
+		Ink: func(c color.Color) Ink {
+			if c == color.White {
+				return Ink{}
+			}
+			return Ink{Name: "PANTONE 186 C", M: 1, Y: 0.81, K: 0.04}
+		},
+	}
+	buf.Reset()
+	a.NotError(img.WritePDF(buf, pr))
+	pdf = buf.String()
+	a.Contains(pdf, "/MediaBox [0 0 30 30] /Resources << /ColorSpace << /CS0 [/Separation /PANTONE#20186#20C /DeviceCMYK << /FunctionType 2 /Domain [0 1] /C0 [0 0 0 0] /C1 [0 1 0.81 0.04] /N 1 >>] >> >>").
+		Contains(pdf, "3 0 0 -3 0 30 cm\n0 0 0 0 k\n0 0 10 10 re\nf\n/CS0 cs 1 scn\nq\n0 0 m\n5 0 l\n5 5 l\n0 5 l\nh\nW n\n1 0 0 1 5 0 cm\n")
+	checkXref(a, pdf, 4)
+
+	fi, err := os.Create("./testdata/print.pdf")
+	a.NotError(err).NotNil(fi)
+	_, err = buf.WriteTo(fi)
+	a.NotError(err)
+	a.NotError(fi.Close()) // 关闭文件
+
+	a.Equal(pdfName("A b(1)"), "/A#20b#281#29")
+}
+
+// 检测 xref 中记录的偏移量是否正确
+func checkXref(a *assert.Assertion, pdf string, objects int) {
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	a.Length(start, 2)
+	xref, err := strconv.Atoi(start[1])
+	a.NotError(err)
+	a.True(strings.HasPrefix(pdf[xref:], "xref\n0 "+strconv.Itoa(objects+1)+"\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllStringSubmatch(pdf[xref:], -1)
+	a.Length(entries, objects)
+	for index, e := range entries {
+		offset, err := strconv.Atoi(e[1])
+		a.NotError(err)
+		a.True(strings.HasPrefix(pdf[offset:], strconv.Itoa(index+1)+" 0 obj\n"), index)
+	}
+
+	// 内容流的长度
+	length := regexp.MustCompile(`/Length (\d+) >>\nstream\n`).FindStringSubmatchIndex(pdf)
+	a.Length(length, 4)
+	n, err := strconv.Atoi(pdf[length[2]:length[3]])
+	a.NotError(err)
+	a.True(strings.HasPrefix(pdf[length[1]+n:], "endstream"))
+}
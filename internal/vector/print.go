@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bufio"
+	"image/color"
+)
+
+// Ink 印刷所用的颜色
+//
+// Name 为空时表示由 C、M、Y、K 组成的印刷色，否则表示名称为 Name 的专色，
+// 此时 C、M、Y、K 为该专色在不支持专色的设备上的替代色。各个分量的取值范围均为 [0,1]。
+type Ink struct {
+	Name       string
+	C, M, Y, K float64
+}
+
+// Print 输出为 PDF 和 EPS 时的设置
+type Print struct {
+	// 图像的宽度，以点(1/72 英寸)为单位，高度按比例计算。
+	Size float64
+
+	// 将颜色转换为印刷所用的颜色
+	//
+	// 为空表示直接采用 RGB 颜色。颜色的透明度会被忽略，完全透明的颜色不会被绘制。
+	Ink func(color.Color) Ink
+}
+
+// 路径操作对应的指令，依次为 MoveTo、LineTo、CubicTo 和 Close。
+type pathOps [4]string
+
+var (
+	pdfOps = pathOps{"m", "l", "c", "h"}
+	epsOps = pathOps{"moveto", "lineto", "curveto", "closepath"}
+)
+
+func writePrintPath(buf *bufio.Writer, p *Path, ops pathOps) {
+	for _, cmd := range p.cmds {
+		switch cmd.Op {
+		case MoveTo, LineTo:
+			writePrintPoints(buf, cmd.Points[:1])
+		case CubicTo:
+			writePrintPoints(buf, cmd.Points[:])
+		}
+		buf.WriteString(ops[cmd.Op])
+		buf.WriteByte('\n')
+	}
+}
+
+func writePrintPoints(buf *bufio.Writer, points []Point) {
+	for _, p := range points {
+		buf.WriteString(formatFloat(p.X))
+		buf.WriteByte(' ')
+		buf.WriteString(formatFloat(p.Y))
+		buf.WriteByte(' ')
+	}
+}
+
+// 返回颜色在 RGB 下的各个分量，如果颜色完全透明，则返回 false。
+func printRGB(c color.Color) (r, g, b float64, ok bool) {
+	if c == nil {
+		return 0, 0, 0, false
+	}
+
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nc.A == 0 {
+		return 0, 0, 0, false
+	}
+	return float64(nc.R) / 0xff, float64(nc.G) / 0xff, float64(nc.B) / 0xff, true
+}
+
+// 图像的实际高度，以点为单位。
+func (img *Image) printHeight(p *Print) float64 {
+	return p.Size * float64(img.Height) / float64(img.Width)
+}
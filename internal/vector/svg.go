@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// WriteSVG 将图像以 SVG 格式输出到 w
+//
+// title 为图像的标题，用于辅助功能，为空表示不输出。
+//
+// clipPath 的 id 以图像内容的 hash 值作为前缀，在同一个 HTML 文档中内联多个不同的图像时不会冲突；
+// 内容相同的图像 id 也相同，但对应的 clipPath 完全一样，并不影响显示的结果。
+func (img *Image) WriteSVG(w io.Writer, title string) error {
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"`,
+		img.Width, img.Height, img.Width, img.Height)
+	if title != "" {
+		buf.WriteString(` role="img"><title>`)
+		if err := xml.EscapeText(buf, []byte(title)); err != nil {
+			return err
+		}
+		buf.WriteString(`</title>`)
+	} else {
+		buf.WriteString(`>`)
+	}
+
+	if fill, ok := svgFill(img.Back); ok {
+		fmt.Fprintf(buf, `<rect width="%d" height="%d"%s/>`, img.Width, img.Height, fill)
+	}
+
+	fill, ok := svgFill(img.Fore)
+	if ok {
+		prefix := img.clipPrefix()
+		var clips int
+		for _, l := range img.Layers {
+			if l.Clip != nil {
+				fmt.Fprintf(buf, `<clipPath id="%s%d"><path d="`, prefix, clips)
+				writeSVGPath(buf, l.Clip)
+				buf.WriteString(`"/></clipPath>`)
+				clips++
+			}
+		}
+
+		buf.WriteString(`<g` + fill + `>`)
+		clips = 0
+		for _, l := range img.Layers {
+			if l.Path.Len() == 0 {
+				if l.Clip != nil {
+					clips++
+				}
+				continue
+			}
+
+			if l.Clip != nil {
+				fmt.Fprintf(buf, `<g clip-path="url(#%s%d)">`, prefix, clips)
+				clips++
+			}
+
+			buf.WriteString(`<path`)
+			if m := l.Transform; m != nil {
+				fmt.Fprintf(buf, ` transform="matrix(%s %s %s %s %s %s)"`,
+					formatFloat(m[0]), formatFloat(m[1]), formatFloat(m[2]),
+					formatFloat(m[3]), formatFloat(m[4]), formatFloat(m[5]))
+			}
+			buf.WriteString(` d="`)
+			writeSVGPath(buf, l.Path)
+			buf.WriteString(`"/>`)
+
+			if l.Clip != nil {
+				buf.WriteString(`</g>`)
+			}
+		}
+		buf.WriteString(`</g>`)
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Flush()
+}
+
+// 返回 clipPath 的 id 前缀
+//
+// 由尺寸、颜色以及所有图层的内容计算得出，没有 clipPath 时返回空值。
+func (img *Image) clipPrefix() string {
+	var hasClip bool
+	for _, l := range img.Layers {
+		if l.Clip != nil {
+			hasClip = true
+			break
+		}
+	}
+	if !hasClip {
+		return ""
+	}
+
+	h := fnv.New64a()
+	buf := bufio.NewWriter(h)
+	fill, _ := svgFill(img.Fore)
+	back, _ := svgFill(img.Back)
+	fmt.Fprintf(buf, "%d %d%s%s", img.Width, img.Height, fill, back)
+	for _, l := range img.Layers {
+		buf.WriteByte(';')
+		writeSVGPath(buf, l.Path)
+		if l.Clip != nil {
+			buf.WriteByte('|')
+			writeSVGPath(buf, l.Clip)
+		}
+		if m := l.Transform; m != nil {
+			for _, v := range m {
+				buf.WriteByte(' ')
+				buf.WriteString(formatFloat(v))
+			}
+		}
+	}
+	buf.Flush()
+
+	return "c" + strconv.FormatUint(h.Sum64(), 36) + "-"
+}
+
+func writeSVGPath(buf *bufio.Writer, p *Path) {
+	var prev Op = -1
+	for _, cmd := range p.cmds {
+		switch cmd.Op {
+		case MoveTo:
+			buf.WriteByte('M')
+			writePoints(buf, cmd.Points[:1])
+		case LineTo:
+			if prev != LineTo {
+				buf.WriteByte('L')
+			} else {
+				buf.WriteByte(' ')
+			}
+			writePoints(buf, cmd.Points[:1])
+		case CubicTo:
+			if prev != CubicTo {
+				buf.WriteByte('C')
+			} else {
+				buf.WriteByte(' ')
+			}
+			writePoints(buf, cmd.Points[:])
+		case Close:
+			buf.WriteByte('Z')
+		}
+		prev = cmd.Op
+	}
+}
+
+func writePoints(buf *bufio.Writer, points []Point) {
+	for i, p := range points {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(formatFloat(p.X))
+		buf.WriteByte(' ')
+		buf.WriteString(formatFloat(p.Y))
+	}
+}
+
+// 返回颜色对应的 fill 属性，如果颜色完全透明，则返回 false。
+func svgFill(c color.Color) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nc.A == 0 {
+		return "", false
+	}
+
+	fill := fmt.Sprintf(` fill="#%02x%02x%02x"`, nc.R, nc.G, nc.B)
+	if nc.A < 0xff {
+		fill += ` fill-opacity="` + formatFloat(float64(nc.A)/0xff) + `"`
+	}
+	return fill, true
+}
+
+// 保留三位小数
+func formatFloat(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 { // 去掉 -0
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestImage_WriteSVG(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Rect(0, 0, 5, 5)
+	p.Polygon(5, 5, 10, 5, 10, 10)
+
+	img := &Image{
+		Width:  10,
+		Height: 10,
+		Back:   color.Transparent,
+		Fore:   color.NRGBA{R: 255, A: 128},
+		Layers: []*Layer{NewLayer(p)},
+	}
+	buf := &bytes.Buffer{}
+	a.NotError(img.WriteSVG(buf, ""))
+	a.Equal(buf.String(), `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10" viewBox="0 0 10 10">`+
+		`<g fill="#ff0000" fill-opacity="0.502"><path d="M0 0L5 0 5 5 0 5ZM5 5L10 5 10 10Z"/></g></svg>`)
+
+	// 背景色、标题和裁剪
+	img.Back = color.White
+	img.Layers = []*Layer{
+		{Path: p, Clip: &Path{}},
+		{Path: &Path{}, Clip: &Path{}},
+		{Path: p, Clip: &Path{}, Transform: &Matrix{-1, 0, 0, 1, 10, 0}},
+	}
+	img.Layers[0].Clip.Rect(0, 0, 5, 10)
+	img.Layers[2].Clip.Rect(5, 0, 5, 10)
+	buf.Reset()
+	a.NotError(img.WriteSVG(buf, "<a&b>"))
+	prefix := img.clipPrefix()
+	a.NotEmpty(prefix).
+		Contains(buf.String(), `role="img"><title>&lt;a&amp;b&gt;</title><rect width="10" height="10" fill="#ffffff"/>`).
+		Contains(buf.String(), `<clipPath id="`+prefix+`0">`).
+		Contains(buf.String(), `<g clip-path="url(#`+prefix+`0)"><path d=`).
+		NotContains(buf.String(), `url(#`+prefix+`1)`).
+		Contains(buf.String(), `<g clip-path="url(#`+prefix+`2)"><path transform="matrix(-1 0 0 1 10 0)" d=`)
+	a.NotError(xml.Unmarshal(buf.Bytes(), &struct{}{}))
+
+	// 内容不同时 id 的前缀也不同
+	img.Layers[2].Clip = &Path{}
+	img.Layers[2].Clip.Rect(4, 0, 6, 10)
+	a.NotEqual(img.clipPrefix(), prefix)
+	img.Layers[2].Clip = &Path{}
+	img.Layers[2].Clip.Rect(5, 0, 5, 10)
+	a.Equal(img.clipPrefix(), prefix)
+	img.Width = 20
+	a.NotEqual(img.clipPrefix(), prefix)
+	a.Empty((&Image{Layers: []*Layer{NewLayer(p)}}).clipPrefix())
+
+	// 前景色透明
+	img.Fore = color.Transparent
+	buf.Reset()
+	a.NotError(img.WriteSVG(buf, ""))
+	a.NotContains(buf.String(), "<path")
+
+	// 圆
+	p = &Path{}
+	p.Circle(50, 50, 40)
+	img = &Image{Width: 100, Height: 100, Back: color.White, Fore: color.Black, Layers: []*Layer{NewLayer(p)}}
+	fi, err := os.Create("./testdata/circle.svg")
+	a.NotError(err).NotNil(fi)
+	a.NotError(img.WriteSVG(fi, "circle"))
+	a.NotError(fi.Close()) // 关闭文件
+}
+
+func TestFormatFloat(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(formatFloat(1), "1").
+		Equal(formatFloat(1.23456), "1.235").
+		Equal(formatFloat(-0.0001), "0").
+		Equal(formatFloat(-1.5), "-1.5")
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package vector 头像的矢量图形
+package vector
+
+import (
+	"image/color"
+	"math"
+)
+
+// 以四段三次贝塞尔曲线近似圆时，控制点与端点的距离与半径之比。
+const kappa = 0.5522847498
+
+// Image 矢量格式的头像
+//
+// 所有图层均以前景色填充，未被填充的部分为背景色。
+type Image struct {
+	Width, Height int
+	Back, Fore    color.Color
+	Layers        []*Layer
+}
+
+// Layer 图层
+type Layer struct {
+	Path      *Path
+	Clip      *Path   // 裁剪区域，为空表示不裁剪。不受 Transform 的影响。
+	Transform *Matrix // 应用于 Path 的变换，为空表示不作变换。
+}
+
+// Matrix 仿射变换矩阵
+//
+// 分别表示 a、b、c、d、e、f，变换之后的坐标为：
+//
+//	x' = a*x + c*y + e
+//	y' = b*x + d*y + f
+type Matrix [6]float64
+
+// Point 坐标点
+type Point struct {
+	X, Y float64
+}
+
+// Op 路径的操作类型
+type Op int8
+
+const (
+	MoveTo  Op = iota // 移动到 Points[0]
+	LineTo            // 直线连接到 Points[0]
+	CubicTo           // 以 Points[0] 和 Points[1] 为控制点，用三次贝塞尔曲线连接到 Points[2]
+	Close             // 闭合当前子路径
+)
+
+// Command 组成路径的单个指令
+type Command struct {
+	Op     Op
+	Points [3]Point
+}
+
+// Path 由多个闭合子路径组成的路径
+//
+// 采用非零环绕规则填充，添加子路径的方法均保证子路径为顺时针方向(y 轴向下)，
+// 以保证重叠部分不会被镂空。
+type Path struct {
+	cmds []Command
+}
+
+// NewLayer 声明仅包含路径 p 的图层
+func NewLayer(p *Path) *Layer { return &Layer{Path: p} }
+
+// Apply 将变换应用于 x,y
+func (m *Matrix) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// Commands 组成路径的所有指令
+func (p *Path) Commands() []Command { return p.cmds }
+
+// Len 指令的数量
+func (p *Path) Len() int { return len(p.cmds) }
+
+// Truncate 仅保留前 n 条指令
+func (p *Path) Truncate(n int) { p.cmds = p.cmds[:n] }
+
+func (p *Path) moveTo(x, y float64) {
+	p.cmds = append(p.cmds, Command{Op: MoveTo, Points: [3]Point{{X: x, Y: y}}})
+}
+
+func (p *Path) lineTo(x, y float64) {
+	p.cmds = append(p.cmds, Command{Op: LineTo, Points: [3]Point{{X: x, Y: y}}})
+}
+
+func (p *Path) cubicTo(x1, y1, x2, y2, x, y float64) {
+	p.cmds = append(p.cmds, Command{Op: CubicTo, Points: [3]Point{{X: x1, Y: y1}, {X: x2, Y: y2}, {X: x, Y: y}}})
+}
+
+func (p *Path) close() {
+	p.cmds = append(p.cmds, Command{Op: Close})
+}
+
+// Polygon 添加由 points 组成的多边形
+//
+// points 每两个元素表示一个顶点，首尾顶点相同时，忽略最后一个顶点。
+func (p *Path) Polygon(points ...float64) {
+	n := len(points) / 2
+	if n > 1 && points[0] == points[2*n-2] && points[1] == points[2*n-1] {
+		n--
+	}
+	if n < 3 {
+		return
+	}
+
+	// 计算有向面积，y 轴向下时，顺时针方向的面积为正。
+	var area float64
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += points[2*i]*points[2*j+1] - points[2*j]*points[2*i+1]
+	}
+
+	if area >= 0 {
+		p.moveTo(points[0], points[1])
+		for i := 1; i < n; i++ {
+			p.lineTo(points[2*i], points[2*i+1])
+		}
+	} else {
+		p.moveTo(points[2*n-2], points[2*n-1])
+		for i := n - 2; i >= 0; i-- {
+			p.lineTo(points[2*i], points[2*i+1])
+		}
+	}
+	p.close()
+}
+
+// Rect 添加矩形
+func (p *Path) Rect(x, y, w, h float64) {
+	p.Polygon(x, y, x+w, y, x+w, y+h, x, y+h)
+}
+
+// Circle 添加圆
+func (p *Path) Circle(cx, cy, r float64) {
+	k := r * kappa
+	p.moveTo(cx, cy-r)
+	p.cubicTo(cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+	p.cubicTo(cx+r, cy+k, cx+k, cy+r, cx, cy+r)
+	p.cubicTo(cx-k, cy+r, cx-r, cy+k, cx-r, cy)
+	p.cubicTo(cx-r, cy-k, cx-k, cy-r, cx, cy-r)
+	p.close()
+}
+
+// RoundedRect 添加圆角矩形
+//
+// r 为圆角的半径，不能大于 w 和 h 的一半。
+func (p *Path) RoundedRect(x, y, w, h, r float64) {
+	r = math.Min(r, math.Min(w, h)/2)
+	if r <= 0 {
+		p.Rect(x, y, w, h)
+		return
+	}
+
+	k := r * (1 - kappa)
+	right, bottom := x+w, y+h
+	p.moveTo(x+r, y)
+	p.lineTo(right-r, y)
+	p.cubicTo(right-k, y, right, y+k, right, y+r)
+	p.lineTo(right, bottom-r)
+	p.cubicTo(right, bottom-k, right-k, bottom, right-r, bottom)
+	p.lineTo(x+r, bottom)
+	p.cubicTo(x+k, bottom, x, bottom-k, x, bottom-r)
+	p.lineTo(x, y+r)
+	p.cubicTo(x, y+k, x+k, y, x+r, y)
+	p.close()
+}
+
+// Sector 添加四分之一的圆环
+//
+// cx,cy 为圆心；r1 和 r2 分别为内外半径，r1 为 0 时即为四分之一的圆；
+// quadrant 表示所在的象限，取值为 [0,3]，从 x 轴正方向开始顺时针(y 轴向下)依次编号。
+func (p *Path) Sector(cx, cy, r1, r2 float64, quadrant int) {
+	ux, uy := quadrantDir(quadrant)
+	vx, vy := quadrantDir(quadrant + 1)
+
+	k := r2 * kappa
+	p.moveTo(cx+r2*ux, cy+r2*uy)
+	p.cubicTo(cx+r2*ux+k*vx, cy+r2*uy+k*vy, cx+r2*vx+k*ux, cy+r2*vy+k*uy, cx+r2*vx, cy+r2*vy)
+	if r1 <= 0 {
+		p.lineTo(cx, cy)
+	} else {
+		k = r1 * kappa
+		p.lineTo(cx+r1*vx, cy+r1*vy)
+		p.cubicTo(cx+r1*vx+k*ux, cy+r1*vy+k*uy, cx+r1*ux+k*vx, cy+r1*uy+k*vy, cx+r1*ux, cy+r1*uy)
+	}
+	p.close()
+}
+
+// 第 q 个象限的起始方向
+func quadrantDir(q int) (x, y float64) {
+	switch q % 4 {
+	case 0:
+		return 1, 0
+	case 1:
+		return 0, 1
+	case 2:
+		return -1, 0
+	default:
+		return 0, -1
+	}
+}
+
+// Ring 添加圆环
+//
+// r1 和 r2 分别为内外半径。与其它方法不同，内圆为逆时针方向，以便在非零环绕规则下镂空。
+func (p *Path) Ring(cx, cy, r1, r2 float64) {
+	p.Circle(cx, cy, r2)
+	if r1 <= 0 {
+		return
+	}
+
+	k := r1 * kappa
+	p.moveTo(cx, cy-r1)
+	p.cubicTo(cx-k, cy-r1, cx-r1, cy-k, cx-r1, cy)
+	p.cubicTo(cx-r1, cy+k, cx-k, cy+r1, cx, cy+r1)
+	p.cubicTo(cx+k, cy+r1, cx+r1, cy+k, cx+r1, cy)
+	p.cubicTo(cx+r1, cy-k, cx+k, cy-r1, cx, cy-r1)
+	p.close()
+}
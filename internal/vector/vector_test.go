@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package vector
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestMatrix_Apply(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := &Matrix{-1, 0, 0, 1, 10, 0}
+	x, y := m.Apply(2, 3)
+	a.Equal(x, 8.0).Equal(y, 3.0)
+
+	m = &Matrix{0, 1, 1, 0, 0, 0}
+	x, y = m.Apply(2, 3)
+	a.Equal(x, 3.0).Equal(y, 2.0)
+}
+
+func TestPath_Polygon(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 顺时针
+	p := &Path{}
+	p.Polygon(0, 0, 10, 0, 10, 10, 0, 0)
+	a.Equal(p.Commands(), []Command{
+		{Op: MoveTo, Points: [3]Point{{X: 0, Y: 0}}},
+		{Op: LineTo, Points: [3]Point{{X: 10, Y: 0}}},
+		{Op: LineTo, Points: [3]Point{{X: 10, Y: 10}}},
+		{Op: Close},
+	})
+
+	// 逆时针，会被调整为顺时针。
+	p = &Path{}
+	p.Polygon(0, 0, 10, 10, 10, 0)
+	a.Equal(p.Commands(), []Command{
+		{Op: MoveTo, Points: [3]Point{{X: 10, Y: 0}}},
+		{Op: LineTo, Points: [3]Point{{X: 10, Y: 10}}},
+		{Op: LineTo, Points: [3]Point{{X: 0, Y: 0}}},
+		{Op: Close},
+	})
+
+	// 少于 3 个顶点
+	p = &Path{}
+	p.Polygon(0, 0, 10, 10, 0, 0)
+	a.Equal(p.Len(), 0)
+
+	p.Rect(0, 0, 5, 5)
+	a.Equal(p.Len(), 5)
+	p.Truncate(0)
+	a.Equal(p.Len(), 0)
+}
+
+func TestPath_Circle(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Circle(5, 5, 5)
+	cmds := p.Commands()
+	a.Length(cmds, 6).
+		Equal(cmds[0].Points[0], Point{X: 5, Y: 0}).
+		Equal(cmds[1].Points[2], Point{X: 10, Y: 5}). // 顺时针
+		Equal(cmds[4].Points[2], Point{X: 5, Y: 0})
+
+	p = &Path{}
+	p.RoundedRect(0, 0, 10, 10, 20) // 半径超过一半，会被限制为 5。
+	cmds = p.Commands()
+	a.Length(cmds, 10).
+		Equal(cmds[0].Points[0], Point{X: 5, Y: 0}).
+		Equal(cmds[1].Points[0], Point{X: 5, Y: 0})
+
+	p = &Path{}
+	p.RoundedRect(0, 0, 10, 10, 0)
+	a.Length(p.Commands(), 5)
+}
+
+func TestPath_Sector(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Sector(0, 0, 5, 10, 0)
+	cmds := p.Commands()
+	a.Length(cmds, 5).
+		Equal(cmds[0].Points[0], Point{X: 10, Y: 0}).
+		Equal(cmds[1].Op, CubicTo).
+		Equal(cmds[1].Points[2], Point{X: 0, Y: 10}). // 顺时针
+		Equal(cmds[2].Points[0], Point{X: 0, Y: 5}).
+		Equal(cmds[3].Points[2], Point{X: 5, Y: 0})
+
+	// 扇形
+	p = &Path{}
+	p.Sector(10, 10, 0, 10, 2)
+	cmds = p.Commands()
+	a.Length(cmds, 4).
+		Equal(cmds[0].Points[0], Point{X: 0, Y: 10}).
+		Equal(cmds[1].Points[2], Point{X: 10, Y: 0}).
+		Equal(cmds[2].Points[0], Point{X: 10, Y: 10})
+}
+
+func TestPath_Ring(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Path{}
+	p.Ring(10, 10, 5, 10)
+	cmds := p.Commands()
+	a.Length(cmds, 12).
+		Equal(cmds[1].Points[2], Point{X: 20, Y: 10}). // 外圆顺时针
+		Equal(cmds[6].Points[0], Point{X: 10, Y: 5}).
+		Equal(cmds[7].Points[2], Point{X: 5, Y: 10}) // 内圆逆时针
+
+	p = &Path{}
+	p.Ring(10, 10, 0, 10)
+	a.Length(p.Commands(), 6)
+}
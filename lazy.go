@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/issue9/identicon/v2/internal/style1"
+	"github.com/issue9/identicon/v2/internal/style2"
+	"github.com/issue9/identicon/v2/internal/style3"
+	"github.com/issue9/identicon/v2/internal/style7"
+)
+
+type indexer interface {
+	ColorIndexAt(x, y int) uint8
+}
+
+// 按需计算像素的图片
+type lazyImage struct {
+	palette color.Palette
+	rect    image.Rectangle
+	indexer indexer
+}
+
+// MakeLazy 根据 data 数据生成一张按需计算像素的图片
+//
+// 其内容与 Make 生成的图片完全相同，但不会为整张图片分配内存，
+// 每个像素的值只在调用 At 或 ColorIndexAt 时才计算，Style1 的方格在第一次访问时整体渲染一次，
+// 调用过 Precompute 时则直接采用预先渲染的方块，
+// 适合尺寸较大或是直接交由编码器输出的场景。
+//
+// 返回值可直接用于 png.Encode、draw.Draw 等需要 image.Image 的地方。
+func (i *Identicon) MakeLazy(data []byte) image.PalettedImage {
+	sum := i.sum(data)
+
+	var idx indexer
+	switch i.style.base() {
+	case Style1:
+		if i.masks != nil {
+			idx = i.masks.Lazy(uint32(sum), i.symmetry)
+		} else {
+			idx = style1.NewLazy(i.size, uint32(sum), i.style.style1Version(), i.symmetry)
+		}
+	case Style2, Style6:
+		idx = style2.NewLazy(i.matrix(data, sum), i.bitsPerPoint, i.shape, i.gap)
+	case Style3:
+		idx = style3.NewGrid(i.size, uint32(sum))
+	case Style4:
+		idx = i.truchet(data)
+	case Style5:
+		idx = i.maze(data)
+	case Style7:
+		idx = style7.NewMandala(i.size, uint32(sum))
+	default:
+		panic("无效的 style")
+	}
+
+	return &lazyImage{
+		palette: i.palette(sum),
+		rect:    i.rect,
+		indexer: idx,
+	}
+}
+
+func (img *lazyImage) ColorModel() color.Model { return img.palette }
+
+func (img *lazyImage) Bounds() image.Rectangle { return img.rect }
+
+func (img *lazyImage) At(x, y int) color.Color {
+	return img.palette[img.ColorIndexAt(x, y)]
+}
+
+func (img *lazyImage) ColorIndexAt(x, y int) uint8 {
+	if !image.Pt(x, y).In(img.rect) {
+		return 0
+	}
+	return img.indexer.ColorIndexAt(x, y)
+}
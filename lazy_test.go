@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_MakeLazy(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, ii := range []*Identicon{S1(size), S1(100), S1(size).Precompute(), S2(size), New(Style3, 100, back, fore), New(Style4, 100, back, fore), New(Style5, 100, back, fore), New(Style6, 96, back, fore).SetShape(ShapeCircle, 1), New(Style7, 100, back, fore)} {
+		for i := 0; i < 10; i++ {
+			data := []byte("lazy-" + strconv.Itoa(i))
+			eager := ii.Make(data)
+			lazy := ii.MakeLazy(data)
+
+			a.Equal(lazy.Bounds(), eager.Bounds()).
+				Equal(lazy.ColorModel(), eager.ColorModel())
+
+			// png.Encode 的输出应该完全相同
+			w1 := &bytes.Buffer{}
+			a.NotError(png.Encode(w1, eager))
+			w2 := &bytes.Buffer{}
+			a.NotError(png.Encode(w2, lazy))
+			a.Equal(w1.Bytes(), w2.Bytes())
+
+			// draw.Draw
+			dst := image.NewRGBA(eager.Bounds())
+			draw.Draw(dst, dst.Rect, lazy, image.Point{}, draw.Src)
+			expected := image.NewRGBA(eager.Bounds())
+			draw.Draw(expected, expected.Rect, eager, image.Point{}, draw.Src)
+			a.Equal(dst.Pix, expected.Pix)
+		}
+	}
+
+	img := S2(size).MakeLazy([]byte("lazy"))
+	a.Equal(img.ColorIndexAt(-1, 0), 0).
+		Equal(img.ColorIndexAt(size, 0), 0)
+
+	fi, err := os.Create("./testdata/lazy.png")
+	a.NotError(err).NotNil(fi)
+	a.NotError(png.Encode(fi, img))
+	a.NotError(fi.Close()) // 关闭文件
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+// Millimeter 1 毫米对应的点数
+//
+// WritePDF 和 WriteEPS 的尺寸均以点(1/72 英寸)为单位，比如 30 毫米可以表示为 30*Millimeter。
+const Millimeter = 72 / 25.4
+
+// Ink 印刷所用的颜色
+//
+// Name 为空时表示由 C、M、Y、K 组成的印刷色，否则表示名称为 Name 的专色，
+// 此时 C、M、Y、K 为该专色在不支持专色的设备上的替代色。各个分量的取值范围均为 [0,1]。
+type Ink = vector.Ink
+
+// CMYK 将 c 转换为 CMYK 印刷色
+//
+// 采用 color.RGBToCMYK 进行简单的转换，没有经过色彩管理，可直接作为 WritePDF 和 WriteEPS 的参数。
+func CMYK(c color.Color) Ink {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	cc, m, y, k := color.RGBToCMYK(nc.R, nc.G, nc.B)
+	return Ink{C: float64(cc) / 0xff, M: float64(m) / 0xff, Y: float64(y) / 0xff, K: float64(k) / 0xff}
+}
+
+// WritePDF 将根据 data 生成的头像以单页 PDF 的格式写入 w
+//
+// 图案与 WriteSVG 相同，页面的大小即为头像的大小。
+// size 为头像的边长，以点(1/72 英寸)为单位；
+// ink 用于将背景色和前景色转换为印刷所用的颜色，可以是 CMYK 或是专色，为空表示采用 RGB 颜色；
+// 颜色的透明度会被忽略，完全透明的颜色不会被绘制。
+func (i *Identicon) WritePDF(w io.Writer, data []byte, size float64, ink func(color.Color) Ink) error {
+	return i.vector(data).WritePDF(w, newPrint(size, ink))
+}
+
+// WriteEPS 将根据 data 生成的头像以 EPS 的格式写入 w
+//
+// 参数与 WritePDF 相同。
+func (i *Identicon) WriteEPS(w io.Writer, data []byte, size float64, ink func(color.Color) Ink) error {
+	return i.vector(data).WriteEPS(w, newPrint(size, ink))
+}
+
+func newPrint(size float64, ink func(color.Color) Ink) *vector.Print {
+	if size <= 0 {
+		panic(fmt.Sprintf("参数 size 的值 %v 必须大于 0", size))
+	}
+	return &vector.Print{Size: size, Ink: ink}
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestCMYK(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(CMYK(color.White), Ink{}).
+		Equal(CMYK(color.Black), Ink{K: 1}).
+		Equal(CMYK(color.NRGBA{R: 255, A: 255}), Ink{M: 1, Y: 1})
+}
+
+func TestIdenticon_WritePDF(t *testing.T) {
+	a := assert.New(t, false)
+	data := []byte("192.168.1.1")
+
+	for name, ii := range map[string]*Identicon{
+		"s1": New(Style1V2, size, color.White, fore),
+		"s2": New(Style2V2, size, color.White, fore),
+	} {
+		buf := &bytes.Buffer{}
+		a.NotError(ii.WritePDF(buf, data, 30*Millimeter, CMYK))
+		pdf := buf.String()
+		a.True(strings.HasPrefix(pdf, "%PDF-1.4\n")).
+			Contains(pdf, "/MediaBox [0 0 85.039 85.039]").
+			Contains(pdf, "\n0 0 0 0 k\n")
+
+		fi, err := os.Create("./testdata/" + name + "-print.pdf")
+		a.NotError(err).NotNil(fi)
+		_, err = buf.WriteTo(fi)
+		a.NotError(err)
+		a.NotError(fi.Close()) // 关闭文件
+
+		buf.Reset()
+		a.NotError(ii.WriteEPS(buf, data, 72, nil))
+		a.True(strings.HasPrefix(buf.String(), "%!PS-Adobe-3.0 EPSF-3.0\n%%BoundingBox: 0 0 72 72\n")).
+			Contains(buf.String(), "\n1 1 1 setrgbcolor\n")
+	}
+
+	a.Panic(func() {
+		S2(size).WritePDF(&bytes.Buffer{}, data, 0, nil)
+	})
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"image"
+	"io"
+
+	"github.com/issue9/identicon/v2/internal/randomart"
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+// Randomart 返回与 ssh-keygen -lv 相同的 randomart 文本
+//
+// digest 为密钥等内容的摘要，比如公钥的 SHA-256 值；
+// title 和 alg 分别显示在上下边框的中间，比如 "ED25519 256" 和 "SHA256"，为空时不显示。
+func Randomart(digest []byte, title, alg string) string {
+	return randomart.New(digest).String(title, alg)
+}
+
+// MakeRandomart 以图片的形式返回 digest 的 randomart
+//
+// 图案与 Randomart 相同，每个位置被经过的次数越多，对应的方块越大。
+// 颜色由 digest 的 hash 值决定，与 Make(digest) 相同。
+func (i *Identicon) MakeRandomart(digest []byte) image.Image {
+	p := image.NewPaletted(i.rect, i.palette(i.sum(digest)))
+	randomart.New(digest).Grid(i.size).Draw(p)
+	return p
+}
+
+// WriteRandomartSVG 将 MakeRandomart 的内容以 SVG 格式写入 w
+func (i *Identicon) WriteRandomartSVG(w io.Writer, digest []byte) error {
+	img := &vector.Image{
+		Width:  i.size,
+		Height: i.size,
+		Back:   i.backColor,
+		Fore:   i.foreColors[i.foreIndex(i.sum(digest))],
+		Layers: []*vector.Layer{vector.NewLayer(randomart.New(digest).Grid(i.size).Path())},
+	}
+	return img.WriteSVG(w, "")
+}
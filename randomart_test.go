@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestRandomart(t *testing.T) {
+	a := assert.New(t, false)
+
+	digest := sha256.Sum256([]byte("randomart"))
+	s := Randomart(digest[:], "ED25519 256", "SHA256")
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	a.Length(lines, 11).
+		Equal(lines[0], "+--[ED25519 256]--+").
+		Equal(lines[10], "+----[SHA256]-----+").
+		Equal(strings.Count(strings.Join(lines[1:10], ""), "S"), 1).
+		Equal(strings.Count(strings.Join(lines[1:10], ""), "E"), 1)
+}
+
+func TestIdenticon_MakeRandomart(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2, size, back, fore)
+	digest := sha256.Sum256([]byte("randomart"))
+
+	img := ii.MakeRandomart(digest[:])
+	a.NotNil(img).Equal(img.Bounds(), image.Rect(0, 0, size, size))
+	p, ok := img.(*image.Paletted)
+	a.True(ok).
+		Equal(p.Palette, ii.Make(digest[:]).(*image.Paletted).Palette)
+
+	fi, err := os.Create("./testdata/randomart.png")
+	a.NotError(err).NotNil(fi)
+	a.NotError(png.Encode(fi, img))
+	a.NotError(fi.Close()) // 关闭文件
+
+	buf := &bytes.Buffer{}
+	a.NotError(ii.WriteRandomartSVG(buf, digest[:]))
+	a.NotError(xml.Unmarshal(buf.Bytes(), &struct{}{})).
+		Contains(buf.String(), `viewBox="0 0 128 128"`)
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/issue9/identicon/v2/internal/flight"
+)
+
+// 合并 Fetch 中相同 Store 和名称的并发渲染
+var fetchGroup = &flight.Group{}
+
+// fetchGroup 中的 key
+//
+// 不直接使用 Store 作为 key 的一部分，即使其类型可比较，其中也可能包含不可比较的值，
+// 比如以接口保存的 map，用作 map 的键时依然会 panic。
+type fetchKey struct {
+	store storeID
+	name  string
+}
+
+// 以指针标识的 Store
+type storeID struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// 返回 store 的标识
+//
+// 仅指针、map 和 chan 类型可以通过地址判断是否为同一个对象，其它类型返回 false。
+// 在 Fetch 执行期间 store 始终被引用，其地址不会被其它对象复用。
+func identify(store Store) (storeID, bool) {
+	v := reflect.ValueOf(store)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan:
+		return storeID{typ: v.Type(), ptr: v.Pointer()}, true
+	default:
+		return storeID{}, false
+	}
+}
+
+// Store 保存已经生成的头像
+//
+// 所有方法都可能在多个 goroutine 中同时调用。
+type Store interface {
+	// Get 返回 name 对应的内容
+	//
+	// 不存在时返回的错误应该可以通过 errors.Is(err, fs.ErrNotExist) 判断。
+	Get(name string) ([]byte, error)
+
+	// Put 保存 name 对应的内容
+	Put(name string, content []byte) error
+}
+
+type dirStore struct {
+	dir string
+}
+
+type memoryStore struct {
+	mux   sync.RWMutex
+	items map[string][]byte
+}
+
+// DirStore 将头像保存在目录 dir 中的 Store 实现
+//
+// name 即为文件相对于 dir 的路径，采用 / 作为分隔符，不存在的目录会被自动创建。
+// 写入时先写入同目录下的临时文件，再重命名为目标文件，读取时不会得到不完整的内容。
+func DirStore(dir string) Store { return &dirStore{dir: dir} }
+
+func (s *dirStore) Get(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(name)))
+}
+
+func (s *dirStore) Put(name string, content []byte) (err error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(name))
+	dir := filepath.Dir(p)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// MemoryStore 将头像保存在内存中的 Store 实现
+//
+// 主要用于测试。Get 返回的内容不能被修改。
+func MemoryStore() Store { return &memoryStore{items: map[string][]byte{}} }
+
+func (s *memoryStore) Get(name string) ([]byte, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if content, found := s.items[name]; found {
+		return content, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (s *memoryStore) Put(name string, content []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.items[name] = append([]byte{}, content...)
+	return nil
+}
+
+// StoreName 返回根据 data 生成的头像在 Store 中的名称
+//
+// 格式为 ab/cd/abcd…-配置的 hash-风格的名称-尺寸.扩展名，比如 ab/cd/abcd…-1a2b3c4d5e6f7a8b-s2v1-128.png。
+// 其中 abcd… 为 data 的 SHA-256 值的前 16 个字节，前两级目录取自其前 4 个字符，以避免单个目录中的文件过多；
+// 配置的 hash 由颜色、对称方式、点阵等所有影响图案的设置计算得出，任何设置的变化都会得到不同的名称；
+// 风格的名称由风格和版本组成，比如 s1v2，不依赖于 Style 的值。
+func (i *Identicon) StoreName(data []byte, format Format) string {
+	digest := sha256.Sum256(data)
+	sum := hex.EncodeToString(digest[:16])
+
+	return sum[:2] + "/" + sum[2:4] + "/" + sum +
+		"-" + strconv.FormatUint(i.configHash(), 16) +
+		"-" + styleNames[i.style] +
+		"-" + strconv.Itoa(i.size) +
+		"." + format.String()
+}
+
+// 计算所有影响图案的设置的 hash 值
+//
+// 风格和尺寸已经包含在 StoreName 中，但依然参与计算。
+func (i *Identicon) configHash() uint64 {
+	h := fnv.New64a()
+	buf := &bytes.Buffer{}
+	write := func(v ...interface{}) {
+		for _, item := range v {
+			binary.Write(buf, binary.LittleEndian, item)
+		}
+	}
+	writeColor := func(c color.Color) {
+		r, g, b, a := c.RGBA()
+		write(r, g, b, a)
+	}
+
+	write(int64(i.style), int64(i.size), int64(i.symmetry), int64(i.blocks), int64(i.shape), int64(i.gap), i.asymmetric)
+	writeColor(i.backColor)
+	write(int64(len(i.foreColors)))
+	for _, c := range i.foreColors {
+		writeColor(c)
+	}
+
+	h.Write(buf.Bytes())
+	return h.Sum64()
+}
+
+// Fetch 从 store 中获取根据 data 生成的头像，不存在时生成并保存到 store 中。
+//
+// 名称由 StoreName 决定，已经保存的头像不会被重新生成。
+// 在同一个 store 上名称相同的并发调用只会生成一次头像，并由这些调用共享结果，
+// 名称中包含了所有的配置，所以配置相同的不同 Identicon 对象之间同样会共享；
+// 不同 store 之间互不影响，各自生成并保存。
+// 是否为同一个 store 由其地址判断，如果 store 的实际类型不是指针、map 或是 chan，则无法判断，此时不会合并。
+// 返回的内容可能被多个调用共享，不能被修改。
+func (i *Identicon) Fetch(store Store, data []byte, format Format) ([]byte, error) {
+	name := i.StoreName(data, format)
+
+	content, err := store.Get(name)
+	if err == nil {
+		return content, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	render := func() ([]byte, error) {
+		buf := &bytes.Buffer{}
+		if err := i.Write(buf, data, format); err != nil {
+			return nil, err
+		}
+		if err := store.Put(name, buf.Bytes()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	id, ok := identify(store)
+	if !ok {
+		return render()
+	}
+	content, err, _ = fetchGroup.Do(fetchKey{store: id, name: name}, render)
+	return content, err
+}
@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 记录 Get 和 Put 的调用次数
+type countStore struct {
+	Store
+	gets, puts int
+}
+
+func (s *countStore) Get(name string) ([]byte, error) {
+	s.gets++
+	return s.Store.Get(name)
+}
+
+func (s *countStore) Put(name string, content []byte) error {
+	s.puts++
+	return s.Store.Put(name, content)
+}
+
+func TestIdenticon_StoreName(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2, size, back, fore)
+	name := ii.StoreName([]byte("caixw"), FormatPNG)
+	a.True(regexp.MustCompile(`^([0-9a-f]{2})/([0-9a-f]{2})/([0-9a-f]{32})-[0-9a-f]+-s2v1-128\.png$`).MatchString(name), name)
+	a.Equal(name[:2], name[6:8]).Equal(name[3:5], name[8:10])
+
+	// 相同的配置和数据得到相同的名称
+	a.Equal(New(Style2, size, back, fore).StoreName([]byte("caixw"), FormatPNG), name)
+
+	// 数据、格式和配置的变化都会改变名称
+	a.NotEqual(ii.StoreName([]byte("caixw1"), FormatPNG), name).
+		NotEqual(ii.StoreName([]byte("caixw"), FormatSVG), name).
+		NotEqual(New(Style2, size, back, fore, back).StoreName([]byte("caixw"), FormatPNG), name).
+		NotEqual(New(Style2, size, fore, back).StoreName([]byte("caixw"), FormatPNG), name).
+		NotEqual(New(Style2V2, size, back, fore).StoreName([]byte("caixw"), FormatPNG), name)
+
+	// 各风格的名称互不相同
+	names := map[string]Style{}
+	for s, n := range styleNames {
+		_, found := names[n]
+		a.False(found, n)
+		names[n] = s
+	}
+	a.True(regexp.MustCompile(`-s7v1-128\.png$`).MatchString(New(Style7V1, size, back, fore).StoreName([]byte("caixw"), FormatPNG)))
+}
+
+func TestMemoryStore(t *testing.T) {
+	a := assert.New(t, false)
+
+	s := MemoryStore()
+	content, err := s.Get("a/b.png")
+	a.ErrorIs(err, fs.ErrNotExist).Nil(content)
+
+	data := []byte("123")
+	a.NotError(s.Put("a/b.png", data))
+	data[0] = '0' // 保存的是副本
+	content, err = s.Get("a/b.png")
+	a.NotError(err).Equal(content, []byte("123"))
+}
+
+func TestDirStore(t *testing.T) {
+	a := assert.New(t, false)
+	dir := filepath.Join("testdata", "store")
+	a.NotError(os.RemoveAll(dir))
+
+	s := DirStore(dir)
+	content, err := s.Get("ab/cd/abcd.png")
+	a.ErrorIs(err, fs.ErrNotExist).Nil(content)
+
+	a.NotError(s.Put("ab/cd/abcd.png", []byte("123")))
+	content, err = s.Get("ab/cd/abcd.png")
+	a.NotError(err).Equal(content, []byte("123"))
+
+	// 覆盖已有的文件
+	a.NotError(s.Put("ab/cd/abcd.png", []byte("456")))
+	content, err = os.ReadFile(filepath.Join(dir, "ab", "cd", "abcd.png"))
+	a.NotError(err).Equal(content, []byte("456"))
+
+	// 没有残留的临时文件
+	entries, err := os.ReadDir(filepath.Join(dir, "ab", "cd"))
+	a.NotError(err).Length(entries, 1)
+
+	// 父目录是文件，无法创建。
+	a.Error(s.Put("ab/cd/abcd.png/x.png", []byte("123")))
+}
+
+func TestIdenticon_Fetch(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2, size, back, fore)
+	s := &countStore{Store: MemoryStore()}
+
+	buf := &bytes.Buffer{}
+	a.NotError(ii.Write(buf, []byte("caixw"), FormatPNG))
+
+	content, err := ii.Fetch(s, []byte("caixw"), FormatPNG)
+	a.NotError(err).Equal(content, buf.Bytes()).
+		Equal(s.gets, 1).
+		Equal(s.puts, 1)
+
+	// 第二次直接从 store 中读取
+	content, err = ii.Fetch(s, []byte("caixw"), FormatPNG)
+	a.NotError(err).Equal(content, buf.Bytes()).
+		Equal(s.gets, 2).
+		Equal(s.puts, 1)
+
+	stored, err := s.Get(ii.StoreName([]byte("caixw"), FormatPNG))
+	a.NotError(err).Equal(stored, buf.Bytes())
+
+	// 已经保存的内容不会被重新生成
+	a.NotError(s.Put(ii.StoreName([]byte("cached"), FormatSVG), []byte("<svg/>")))
+	content, err = ii.Fetch(s, []byte("cached"), FormatSVG)
+	a.NotError(err).Equal(content, []byte("<svg/>"))
+
+	// Get 返回其它错误
+	errGet := errors.New("get")
+	fail := &failStore{get: errGet}
+	content, err = ii.Fetch(fail, []byte("caixw"), FormatPNG)
+	a.ErrorIs(err, errGet).Nil(content)
+
+	// Put 返回错误
+	errPut := errors.New("put")
+	fail = &failStore{get: fs.ErrNotExist, put: errPut}
+	content, err = ii.Fetch(fail, []byte("caixw"), FormatPNG)
+	a.ErrorIs(err, errPut).Nil(content)
+
+	// DirStore
+	dir := filepath.Join("testdata", "store-fetch")
+	a.NotError(os.RemoveAll(dir))
+	content, err = ii.Fetch(DirStore(dir), []byte("caixw"), FormatPNG)
+	a.NotError(err).Equal(content, buf.Bytes())
+	content, err = os.ReadFile(filepath.Join(dir, filepath.FromSlash(ii.StoreName([]byte("caixw"), FormatPNG))))
+	a.NotError(err).Equal(content, buf.Bytes())
+}
+
+// Put 在 release 关闭之前一直阻塞
+type blockStore struct {
+	Store
+	puts    int32
+	release chan struct{}
+}
+
+func (s *blockStore) Put(name string, content []byte) error {
+	atomic.AddInt32(&s.puts, 1)
+	<-s.release
+	return s.Store.Put(name, content)
+}
+
+func TestIdenticon_Fetch_concurrent(t *testing.T) {
+	a := assert.New(t, false)
+	ii := New(Style2, size, back, fore)
+	s := &blockStore{Store: MemoryStore(), release: make(chan struct{})}
+	id, ok := identify(s)
+	a.True(ok)
+	key := fetchKey{store: id, name: ii.StoreName([]byte("caixw"), FormatPNG)}
+
+	// 记录实际绘制的次数
+	var draws int32
+	png := encoders[FormatPNG]
+	encoders[FormatPNG] = &encoder{name: png.name, contentType: png.contentType, encode: func(w io.Writer, i *Identicon, data []byte) error {
+		atomic.AddInt32(&draws, 1)
+		return png.encode(w, i, data)
+	}}
+	defer func() { encoders[FormatPNG] = png }()
+
+	const n = 20
+	results := make([][]byte, n)
+	errs := make([]error, n)
+	wg := &sync.WaitGroup{}
+	fetch := func(index int) {
+		defer wg.Done()
+		results[index], errs[index] = ii.Fetch(s, []byte("caixw"), FormatPNG)
+	}
+
+	// 第一个调用阻塞在 Put 中，之后的调用都在等待其结果。
+	wg.Add(1)
+	go fetch(0)
+	for atomic.LoadInt32(&s.puts) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for index := 1; index < n; index++ {
+		wg.Add(1)
+		go fetch(index)
+	}
+	for fetchGroup.Waiters(key) != n-1 {
+		time.Sleep(time.Millisecond)
+	}
+	a.Equal(atomic.LoadInt32(&draws), 1)
+
+	// 其它 store 不会等待，且内容会被保存到该 store 中。
+	other := MemoryStore()
+	content, err := ii.Fetch(other, []byte("caixw"), FormatPNG)
+	a.NotError(err).NotEmpty(content)
+	stored, err := other.Get(key.name)
+	a.NotError(err).Equal(stored, content)
+	a.Equal(fetchGroup.Waiters(key), n-1).
+		Equal(atomic.LoadInt32(&draws), 2)
+
+	// n 个并发调用只绘制了一次
+	close(s.release)
+	wg.Wait()
+	a.Equal(atomic.LoadInt32(&draws), 2)
+
+	buf := &bytes.Buffer{}
+	a.NotError(ii.Write(buf, []byte("caixw"), FormatPNG))
+	a.Equal(atomic.LoadInt32(&s.puts), 1)
+	for index := 0; index < n; index++ {
+		a.NotError(errs[index]).Equal(results[index], buf.Bytes())
+	}
+	a.Equal(fetchGroup.Waiters(key), -1)
+	stored, err = s.Get(key.name)
+	a.NotError(err).Equal(stored, buf.Bytes())
+
+	// 不可比较的 Store 同样可用
+	m := mapStore{}
+	content, err = ii.Fetch(m, []byte("caixw"), FormatPNG)
+	a.NotError(err).Equal(content, buf.Bytes()).Equal(m[key.name], buf.Bytes())
+
+	// 可比较的类型中包含不可比较的值，不会 panic。
+	w := wrapStore{Store: mapStore{}}
+	content, err = ii.Fetch(w, []byte("caixw"), FormatPNG)
+	a.NotError(err).Equal(content, buf.Bytes())
+	_, ok = identify(w)
+	a.False(ok)
+}
+
+func TestIdentify(t *testing.T) {
+	a := assert.New(t, false)
+
+	s1, s2 := MemoryStore(), MemoryStore()
+	id1, ok := identify(s1)
+	a.True(ok)
+	id, ok := identify(s1)
+	a.True(ok).Equal(id, id1)
+	id2, ok := identify(s2)
+	a.True(ok).NotEqual(id2, id1)
+
+	m := mapStore{}
+	id, ok = identify(m)
+	a.True(ok).NotEqual(id, id1)
+
+	_, ok = identify(wrapStore{Store: s1})
+	a.False(ok)
+}
+
+// 可比较的 Store，但是包含了不可比较的值。
+type wrapStore struct {
+	Store
+}
+
+// 不可比较的 Store
+type mapStore map[string][]byte
+
+func (s mapStore) Get(name string) ([]byte, error) {
+	if content, found := s[name]; found {
+		return content, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (s mapStore) Put(name string, content []byte) error {
+	s[name] = content
+	return nil
+}
+
+type failStore struct {
+	get, put error
+}
+
+func (s *failStore) Get(string) ([]byte, error) { return nil, s.get }
+
+func (s *failStore) Put(string, []byte) error { return s.put }
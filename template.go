@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"strconv"
+)
+
+// DataURI 返回根据 data 生成的头像的 data URI
+//
+// 返回值为 base64 编码的 data URI，MIME 类型由 format 决定，可直接用于 HTML 模板中的 src 属性。
+func (i *Identicon) DataURI(data []byte, format Format) (template.URL, error) {
+	e := format.Encoder()
+	buf := &bytes.Buffer{}
+	if err := e.Encode(buf, i, data); err != nil {
+		return "", err
+	}
+	return template.URL("data:" + e.ContentType() + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// FuncMap 返回可用于 html/template 的函数
+//
+// 包含以下函数：
+//   - identiconSVG data title：返回内联的 SVG，title 会作为 SVG 的 <title> 元素，为空表示不输出；
+//   - identiconImg data alt：返回以 PNG 格式的 data URI 作为 src 的 <img> 元素；
+//
+// 参数均会被正确转义，返回值为 template.HTML，可以直接输出到模板中。
+func (i *Identicon) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"identiconSVG": func(data, title string) (template.HTML, error) {
+			buf := &bytes.Buffer{}
+			if err := i.vector([]byte(data)).WriteSVG(buf, title); err != nil {
+				return "", err
+			}
+			return template.HTML(buf.String()), nil
+		},
+
+		"identiconImg": func(data, alt string) (template.HTML, error) {
+			src, err := i.DataURI([]byte(data), FormatPNG)
+			if err != nil {
+				return "", err
+			}
+
+			size := strconv.Itoa(i.size)
+			return template.HTML(`<img src="` + string(src) + `" width="` + size + `" height="` + size +
+				`" alt="` + template.HTMLEscapeString(alt) + `">`), nil
+		},
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"image"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_DataURI(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2V2, size, back, fore)
+	data := []byte("192.168.1.1")
+
+	uri, err := ii.DataURI(data, FormatPNG)
+	a.NotError(err).True(strings.HasPrefix(string(uri), "data:image/png;base64,"))
+	bs, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(uri), "data:image/png;base64,"))
+	a.NotError(err)
+	a.Equal(bs[24], 1) // IHDR 中的位深度
+	img, err := png.Decode(bytes.NewReader(bs))
+	a.NotError(err).Equal(img.(*image.Paletted).Pix, ii.Make(data).(*image.Paletted).Pix)
+
+	uri, err = ii.DataURI(data, FormatSVG)
+	a.NotError(err).True(strings.HasPrefix(string(uri), "data:image/svg+xml;base64,"))
+	bs, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(string(uri), "data:image/svg+xml;base64,"))
+	a.NotError(err)
+	svg := &bytes.Buffer{}
+	a.NotError(ii.WriteSVG(svg, data))
+	a.Equal(bs, svg.Bytes())
+
+	a.Panic(func() {
+		ii.DataURI(data, 100)
+	})
+}
+
+func TestIdenticon_FuncMap(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2V2, size, back, fore)
+	tpl, err := template.New("t").Funcs(ii.FuncMap()).Parse(`<p>{{identiconSVG .Data .Name}}</p><a href="/">{{identiconImg .Data .Name}}</a>`)
+	a.NotError(err)
+
+	buf := &bytes.Buffer{}
+	a.NotError(tpl.Execute(buf, map[string]string{"Data": "192.168.1.1", "Name": `<caixw> & "co"`}))
+	html := buf.String()
+
+	a.Contains(html, `<p><svg xmlns="http://www.w3.org/2000/svg" width="128" height="128" viewBox="0 0 128 128" role="img"><title>&lt;caixw&gt; &amp; &#34;co&#34;</title>`).
+		Contains(html, `<a href="/"><img src="data:image/png;base64,`).
+		Contains(html, `" width="128" height="128" alt="&lt;caixw&gt; &amp; &#34;co&#34;"></a>`)
+
+	// 与 DataURI 的内容相同
+	uri, err := ii.DataURI([]byte("192.168.1.1"), FormatPNG)
+	a.NotError(err).Contains(html, `src="`+string(uri)+`"`)
+}
+
+func TestIdenticon_FuncMap_clipPath(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 同一页面中内联多个不同的头像，clipPath 的 id 不能重复。
+	small := New(Style1, 48, back, fore).SetSymmetry(SymmetryMirrorX)
+	large := New(Style1, size, back, fore).SetSymmetry(SymmetryMirrorXY)
+	tpl, err := template.New("t").
+		Funcs(template.FuncMap{"small": small.FuncMap()["identiconSVG"], "large": large.FuncMap()["identiconSVG"]}).
+		Parse(`{{small "caixw" ""}}{{large "caixw" ""}}{{large "192.168.1.1" ""}}`)
+	a.NotError(err)
+
+	buf := &bytes.Buffer{}
+	a.NotError(tpl.Execute(buf, nil))
+	svgs := strings.SplitAfter(buf.String(), "</svg>")
+	a.Length(svgs, 4).Empty(svgs[3])
+
+	idExp := regexp.MustCompile(`<clipPath id="([^"]+)"`)
+	urlExp := regexp.MustCompile(`url\(#([^)]+)\)`)
+	ids := map[string]int{}
+	for index, svg := range svgs[:3] {
+		matches := idExp.FindAllStringSubmatch(svg, -1)
+		a.True(len(matches) > 0, svg)
+		for _, m := range matches {
+			_, found := ids[m[1]]
+			a.False(found, m[1])
+			ids[m[1]] = index
+		}
+
+		// 引用的均为同一个 SVG 中的 clipPath
+		for _, m := range urlExp.FindAllStringSubmatch(svg, -1) {
+			i, found := ids[m[1]]
+			a.True(found, m[1]).Equal(i, index)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/issue9/identicon/v2/internal/term"
+)
+
+// TermColor 终端的颜色模式
+type TermColor = term.Mode
+
+const (
+	TermTrueColor = term.TrueColor // 24 位真彩色，默认值。
+	Term256       = term.Color256  // 256 色
+	TermMono      = term.Mono      // 单色，不输出任何颜色的转义字符。
+)
+
+// TermGlyph 在终端中表示像素的字符
+type TermGlyph int8
+
+const (
+	TermHalfBlock TermGlyph = iota // 半块字符，每个字符表示上下两个像素，默认值。
+	TermBraille                    // 盲文字符，每个字符表示 2x4 个像素。
+)
+
+// Terminal 返回可以直接输出到终端的头像
+//
+// width 为头像在水平和垂直方向上的像素数量，为 0 时，Style2 和 Style6 采用点阵的行数，
+// 其它风格为 16。当 width 与点阵的行数相同时，直接输出点阵的内容，
+// 否则从 MakeLazy 生成的图片中按像素的中心点取样；
+// 背景色完全透明时不输出背景色，由终端的背景色代替。
+//
+// 返回值由多行组成，每一行都以换行符结尾。
+func (i *Identicon) Terminal(data []byte, width int, mode TermColor, glyph TermGlyph) string {
+	if width < 0 {
+		panic(fmt.Sprintf("参数 width 的值 %d 不能小于 0", width))
+	}
+
+	sum := i.sum(data)
+	fore := i.foreColors[i.foreIndex(sum)]
+
+	var src term.Source
+	base := i.style.base()
+	if (base == Style2 || base == Style6) && (width == 0 || width == i.blocks) {
+		width = i.blocks
+		m := i.matrix(data, sum)
+		src = func(x, y int) bool { return m.ColorIndex(x, y) == 1 }
+	} else {
+		if width == 0 {
+			width = 16
+		}
+		img := i.MakeLazy(data)
+		src = func(x, y int) bool {
+			return img.ColorIndexAt((2*x+1)*i.size/(2*width), (2*y+1)*i.size/(2*width)) == 1
+		}
+	}
+
+	if glyph == TermBraille {
+		return term.Braille(src, width, width, fore, i.backColor, mode)
+	}
+	return term.HalfBlock(src, width, width, fore, i.backColor, mode)
+}
+
+// EncodeSixel 将 img 以 Sixel 格式写入 w
+//
+// img 通常为 Make 或是 MakeLazy 的返回值，必须实现了 image.PalettedImage，
+// 且 ColorModel 为 color.Palette。完全透明的颜色不会被绘制，由终端的背景色代替。
+func EncodeSixel(w io.Writer, img image.Image) error { return term.Sixel(w, img) }
+
+// EncodeKitty 将 img 以 Kitty 图形协议的转义字符写入 w
+//
+// 像素以未压缩的 RGBA 格式传输，在支持该协议的终端中会直接显示图片。
+func EncodeKitty(w io.Writer, img image.Image) error { return term.Kitty(w, img) }
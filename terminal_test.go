@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_Terminal(t *testing.T) {
+	a := assert.New(t, false)
+	data := []byte("192.168.1.1")
+
+	// Style2 直接输出点阵的内容
+	ii := New(Style2V2, size, back, fore)
+	rows := ii.Describe(data).FormatRows()
+	lines := strings.Split(strings.TrimSuffix(ii.Terminal(data, 0, TermMono, TermHalfBlock), "\n"), "\n")
+	a.Length(lines, 4)
+	for y, line := range lines {
+		var x int
+		for _, r := range line {
+			top, bottom := rows[2*y][x] == '1', rows[2*y+1][x] == '1'
+			switch r {
+			case '█':
+				a.True(top && bottom)
+			case '▀':
+				a.True(top && !bottom)
+			case '▄':
+				a.True(!top && bottom)
+			default:
+				a.True(!top && !bottom)
+			}
+			x++
+		}
+		a.Equal(x, 8)
+	}
+
+	// 与 Make 采用相同的颜色
+	c := color.NRGBAModel.Convert(ii.Make(data).(*image.Paletted).Palette[1]).(color.NRGBA)
+	s := ii.Terminal(data, 0, TermTrueColor, TermBraille)
+	a.True(strings.HasPrefix(s, "\x1b[48;2;")).
+		Contains(s, fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)).
+		Equal(strings.Count(s, "\n"), 2)
+
+	// 其它的风格取样
+	for _, ii := range []*Identicon{S1(size), New(Style3, size, back, fore), New(Style6, size, back, fore), New(Style7, size, back, fore)} {
+		s := ii.Terminal(data, 0, TermMono, TermHalfBlock)
+		lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+		a.Length(lines, 8)
+		for _, line := range lines {
+			a.Equal(utf8.RuneCountInString(line), 16)
+		}
+
+		s = ii.Terminal(data, 24, TermMono, TermBraille)
+		a.Equal(strings.Count(s, "\n"), 6)
+	}
+
+	a.Panic(func() {
+		ii.Terminal(data, -1, TermMono, TermHalfBlock)
+	})
+}
+
+func TestEncodeSixel(t *testing.T) {
+	a := assert.New(t, false)
+
+	ii := New(Style2V2, size, back, fore)
+	data := []byte("192.168.1.1")
+
+	eager := &bytes.Buffer{}
+	a.NotError(EncodeSixel(eager, ii.Make(data)))
+	lazy := &bytes.Buffer{}
+	a.NotError(EncodeSixel(lazy, ii.MakeLazy(data)))
+	a.Equal(eager.Bytes(), lazy.Bytes()).
+		True(bytes.HasPrefix(eager.Bytes(), []byte("\x1bP0;1;0q\"1;1;128;128#0;2;"))).
+		True(bytes.HasSuffix(eager.Bytes(), []byte("\x1b\\")))
+
+	a.Error(EncodeSixel(eager, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+}
+
+func TestEncodeKitty(t *testing.T) {
+	a := assert.New(t, false)
+
+	buf := &bytes.Buffer{}
+	a.NotError(EncodeKitty(buf, S2(size).Make([]byte("192.168.1.1"))))
+	a.True(bytes.HasPrefix(buf.Bytes(), []byte("\x1b_Ga=T,f=32,s=128,v=128,m=1;"))).
+		True(bytes.HasSuffix(buf.Bytes(), []byte("\x1b\\")))
+}
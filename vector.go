@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"io"
+
+	"github.com/issue9/identicon/v2/internal/style1"
+	"github.com/issue9/identicon/v2/internal/style3"
+	"github.com/issue9/identicon/v2/internal/style7"
+	"github.com/issue9/identicon/v2/internal/vector"
+)
+
+// WriteSVG 将根据 data 生成的头像以 SVG 格式写入 w
+//
+// 其内容与 Make 生成的图片相同，但以矢量的形式表示，可以任意缩放。
+func (i *Identicon) WriteSVG(w io.Writer, data []byte) error {
+	return i.vector(data).WriteSVG(w, "")
+}
+
+// 生成矢量格式的头像
+func (i *Identicon) vector(data []byte) *vector.Image {
+	sum := i.sum(data)
+	img := &vector.Image{
+		Width:  i.size,
+		Height: i.size,
+		Back:   i.backColor,
+		Fore:   i.foreColors[i.foreIndex(sum)],
+	}
+
+	switch i.style.base() {
+	case Style1:
+		img.Layers = style1.Vector(i.size, uint32(sum), i.style.style1Version(), i.symmetry)
+	case Style2, Style6:
+		p := i.matrix(data, sum).Path(i.bitsPerPoint, i.shape, i.gap)
+		img.Layers = []*vector.Layer{vector.NewLayer(p)}
+	case Style3:
+		p := style3.NewGrid(i.size, uint32(sum)).Path()
+		img.Layers = []*vector.Layer{vector.NewLayer(p)}
+	case Style4:
+		img.Layers = []*vector.Layer{vector.NewLayer(i.truchet(data).Path())}
+	case Style5:
+		img.Layers = []*vector.Layer{vector.NewLayer(i.maze(data).Path())}
+	case Style7:
+		p := style7.NewMandala(i.size, uint32(sum)).Path()
+		img.Layers = []*vector.Layer{vector.NewLayer(p)}
+	default:
+		panic("无效的 style")
+	}
+
+	return img
+}
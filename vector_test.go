@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2015-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package identicon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestIdenticon_WriteSVG(t *testing.T) {
+	a := assert.New(t, false)
+
+	items := map[string]*Identicon{
+		"s1":          S1(size),
+		"s1v2":        New(Style1V2, size, back, fore),
+		"s1-mirror":   New(Style1V2, size, back, fore).SetSymmetry(SymmetryMirrorXY),
+		"s1-diagonal": New(Style1V2, size, back, fore).SetSymmetry(SymmetryDiagonal),
+		"s2":          S2(size),
+		"s2-circle":   New(Style2V2, size, back, fore).SetShape(ShapeCircle, 2),
+		"s2-hexagon":  New(Style2V2, size, back, fore).SetGrid(16).SetShape(ShapeHexagon, 0),
+		"s3":          New(Style3, size, back, fore),
+		"s4":          New(Style4, size, back, fore),
+		"s5":          New(Style5, size, back, fore),
+		"s6":          New(Style6, size, back, fore),
+		"s7":          New(Style7, size, back, fore),
+	}
+
+	for name, ii := range items {
+		for i := 0; i < 3; i++ {
+			data := []byte("svg-" + strconv.Itoa(i))
+			buf := &bytes.Buffer{}
+			a.NotError(ii.WriteSVG(buf, data))
+			a.NotError(xml.Unmarshal(buf.Bytes(), &struct{}{}), name)
+			a.Contains(buf.String(), `viewBox="0 0 128 128"`)
+
+			img := ii.vector(data)
+			a.Equal(img.Fore, ii.Make(data).(*image.Paletted).Palette[1])
+
+			fi, err := os.Create("./testdata/" + name + "-" + strconv.Itoa(i) + ".svg")
+			a.NotError(err).NotNil(fi)
+			_, err = buf.WriteTo(fi)
+			a.NotError(err)
+			a.NotError(fi.Close()) // 关闭文件
+		}
+	}
+}